@@ -0,0 +1,216 @@
+// Package ansi draws levels as text in a terminal, with ANSI true color
+// escapes.
+package ansi
+
+import (
+	"bufio"
+	"fmt"
+	"goldtk"
+	"image/color"
+	"io"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options controls how Render draws a level.
+type Options struct {
+	// CellSize is the size in pixels of the level area shown by a single
+	// character. It defaults to the smallest grid size of the level layers.
+	CellSize int
+
+	// Viewport is the area of the level to draw, in cells. Its position
+	// scrolls the level, and a zero width or height extends it to the level
+	// edge.
+	Viewport goldtk.GridRect
+
+	// EntityGlyphs overrides the glyph of entities by identifier. Other
+	// entities show the first letter of their identifier.
+	EntityGlyphs map[goldtk.Identifier]rune
+
+	// IntGridGlyphs overrides the glyph of IntGrid values, by value
+	// identifier, or by value number for values without identifier. Other
+	// values show as a block, or as the first letter of their identifier
+	// when NoColor is set.
+	IntGridGlyphs map[string]rune
+
+	// Wide prints every cell twice, so that cells look square in most
+	// terminal fonts.
+	Wide bool
+
+	// NoColor leaves out ANSI escapes.
+	NoColor bool
+}
+
+// glyph is the content of a single cell.
+type glyph struct {
+	r rune
+	c color.RGBA
+}
+
+// source returns the glyph of a layer for a cell, centered on p.
+type source func(cx, cy int, p goldtk.LevelPoint) (glyph, bool)
+
+// Render writes the level to w, one line per row of cells.
+func Render(w io.Writer, lvl goldtk.Level, opts Options) error {
+	size := opts.CellSize
+	if size <= 0 {
+		size = cellSize(lvl)
+	}
+
+	cols := (lvl.PxWidth() + size - 1) / size
+	rows := (lvl.PxHeight() + size - 1) / size
+	view := viewport(opts.Viewport, cols, rows)
+
+	// The first layer instance is the top-most one, and the first source
+	// with a glyph wins.
+	sources := make([]source, 0)
+	for _, lyr := range lvl.Layers() {
+		if !lyr.IsVisible() {
+			continue
+		}
+
+		switch lyr.Type() {
+		case goldtk.EntityLayer:
+			sources = append(sources, entitySource(lyr, size, opts))
+		case goldtk.IntGridLayer:
+			sources = append(sources, intGridSource(lyr, opts))
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	bg := lvl.BgColor().RGBA()
+
+	for cy := view.CY; cy < view.CY+view.H; cy++ {
+		var current *color.RGBA
+		if !opts.NoColor {
+			fmt.Fprintf(bw, "\x1b[48;2;%d;%d;%dm", bg.R, bg.G, bg.B)
+		}
+
+		for cx := view.CX; cx < view.CX+view.W; cx++ {
+			p := goldtk.LevelPoint{X: cx*size + size/2, Y: cy*size + size/2}
+
+			g := glyph{r: ' '}
+			for _, src := range sources {
+				if found, ok := src(cx, cy, p); ok {
+					g = found
+					break
+				}
+			}
+
+			if !opts.NoColor && g.r != ' ' && (current == nil || *current != g.c) {
+				fmt.Fprintf(bw, "\x1b[38;2;%d;%d;%dm", g.c.R, g.c.G, g.c.B)
+				current = &g.c
+			}
+
+			bw.WriteRune(g.r)
+			if opts.Wide {
+				bw.WriteRune(g.r)
+			}
+		}
+
+		if !opts.NoColor {
+			bw.WriteString("\x1b[0m")
+		}
+		bw.WriteByte('\n')
+	}
+
+	return bw.Flush()
+}
+
+// cellSize returns the smallest grid size of the level layers.
+func cellSize(lvl goldtk.Level) int {
+	size := 0
+	for _, lyr := range lvl.Layers() {
+		if s := lyr.GridSizeInPx(); s > 0 && (size == 0 || s < size) {
+			size = s
+		}
+	}
+
+	if size == 0 {
+		return 16
+	}
+
+	return size
+}
+
+// viewport clamps the requested area to the level cells.
+func viewport(view goldtk.GridRect, cols, rows int) goldtk.GridRect {
+	view.CX = max(0, min(view.CX, cols))
+	view.CY = max(0, min(view.CY, rows))
+
+	if view.W <= 0 || view.CX+view.W > cols {
+		view.W = cols - view.CX
+	}
+	if view.H <= 0 || view.CY+view.H > rows {
+		view.H = rows - view.CY
+	}
+
+	return view
+}
+
+// entitySource shows entities in the cell holding their pivot.
+func entitySource(lyr goldtk.Layer, size int, opts Options) source {
+	cells := make(map[goldtk.GridPoint]glyph)
+	for _, e := range lyr.Entities() {
+		pos := e.LevelPos()
+		if pos.X < 0 || pos.Y < 0 {
+			continue
+		}
+
+		cell := goldtk.GridPoint{CX: pos.X / size, CY: pos.Y / size}
+		if _, ok := cells[cell]; ok {
+			continue
+		}
+
+		g := glyph{r: '?', c: color.RGBA{R: 255, G: 255, B: 255, A: 255}}
+		if r, ok := opts.EntityGlyphs[e.Identifier()]; ok {
+			g.r = r
+		} else if r, _ := utf8.DecodeRuneInString(string(e.Identifier())); r != utf8.RuneError {
+			g.r = r
+		}
+		if def := e.Def(); def != nil {
+			g.c = def.Color().RGBA()
+		}
+
+		cells[cell] = g
+	}
+
+	return func(cx, cy int, _ goldtk.LevelPoint) (glyph, bool) {
+		g, ok := cells[goldtk.GridPoint{CX: cx, CY: cy}]
+		return g, ok
+	}
+}
+
+// intGridSource shows the IntGrid value under the center of the cell.
+func intGridSource(lyr goldtk.Layer, opts Options) source {
+	def := lyr.Def()
+	space := lyr.Space()
+	grid := lyr.IntGrid()
+
+	glyphs := make(map[int]glyph)
+	if def != nil {
+		for _, v := range def.IntGridValues() {
+			name := strconv.Itoa(v.Value())
+			if id, ok := v.Identifier().Get(); ok {
+				name = id
+			}
+
+			g := glyph{r: '█', c: v.Color().RGBA()}
+			if r, ok := opts.IntGridGlyphs[name]; ok {
+				g.r = r
+			} else if opts.NoColor {
+				r, _ := utf8.DecodeRuneInString(name)
+				g.r = unicode.ToLower(r)
+			}
+
+			glyphs[v.Value()] = g
+		}
+	}
+
+	return func(_, _ int, p goldtk.LevelPoint) (glyph, bool) {
+		cell := space.LevelToGrid(p)
+		g, ok := glyphs[grid.At(cell.CX, cell.CY)]
+		return g, ok
+	}
+}
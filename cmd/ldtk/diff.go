@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"goldtk/diff"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Show the changes between two versions of a project",
+	Long: `Show the changes between two versions of a project: definitions, levels,
+layers, entities, fields, IntGrid cells and tiles. Instances are matched by
+their IID and definitions by their UID, so reordered arrays are not reported.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := load(args[0])
+		if err != nil {
+			return err
+		}
+
+		b, err := load(args[1])
+		if err != nil {
+			return err
+		}
+
+		changes := diff.Compare(a, b)
+		if jsonOutput {
+			return printJSON(os.Stdout, changes)
+		}
+
+		for _, c := range changes {
+			fmt.Println(c)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"goldtk"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+type entityInfo struct {
+	Identifier goldtk.Identifier         `json:"identifier"`
+	Iid        goldtk.InstanceIdentifier `json:"iid"`
+	Level      goldtk.Identifier         `json:"level"`
+	Layer      goldtk.Identifier         `json:"layer"`
+	Tags       []string                  `json:"tags"`
+	X          int                       `json:"x"`
+	Y          int                       `json:"y"`
+	Width      int64                     `json:"width"`
+	Height     int64                     `json:"height"`
+	Fields     map[string]any            `json:"fields"`
+}
+
+// filter matches an entity property against a path.Match pattern.
+type filter struct {
+	key     string
+	pattern string
+}
+
+func parseFilter(s string) (filter, error) {
+	key, pattern, ok := strings.Cut(s, "=")
+	if !ok {
+		return filter{}, fmt.Errorf("filter %q is not key=pattern", s)
+	}
+
+	switch {
+	case key == "identifier", key == "iid", key == "level", key == "layer", key == "tag":
+	case strings.HasPrefix(key, "field."):
+	default:
+		return filter{}, fmt.Errorf("filter %q: unknown key %s", s, key)
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return filter{}, fmt.Errorf("filter %q: %w", s, err)
+	}
+
+	return filter{key: key, pattern: pattern}, nil
+}
+
+func (f filter) match(e entityInfo) bool {
+	match := func(s string) bool {
+		ok, _ := path.Match(f.pattern, s)
+		return ok
+	}
+
+	switch f.key {
+	case "identifier":
+		return match(string(e.Identifier))
+	case "iid":
+		return match(string(e.Iid))
+	case "level":
+		return match(string(e.Level))
+	case "layer":
+		return match(string(e.Layer))
+	case "tag":
+		return slices.ContainsFunc(e.Tags, match)
+	}
+
+	v, ok := e.Fields[strings.TrimPrefix(f.key, "field.")]
+	return ok && match(fmt.Sprint(v))
+}
+
+var entityFilters []string
+
+var entitiesCmd = &cobra.Command{
+	Use:   "entities <project>",
+	Short: "List entities, optionally filtered",
+	Long: `List the entities of every level.
+
+Filters are key=pattern pairs, where pattern is a shell glob. Keys are
+identifier, iid, level, layer, tag and field.<identifier>. An entity is listed
+when it matches every filter.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filters := make([]filter, 0, len(entityFilters))
+		for _, s := range entityFilters {
+			f, err := parseFilter(s)
+			if err != nil {
+				return err
+			}
+			filters = append(filters, f)
+		}
+
+		r, err := load(args[0])
+		if err != nil {
+			return err
+		}
+
+		entities := make([]entityInfo, 0)
+		for _, lvl := range r.Levels() {
+			for _, lyr := range lvl.Layers() {
+				for _, e := range lyr.Entities() {
+					info := newEntityInfo(lvl, lyr, e)
+					if !slices.ContainsFunc(filters, func(f filter) bool { return !f.match(info) }) {
+						entities = append(entities, info)
+					}
+				}
+			}
+		}
+
+		if jsonOutput {
+			return printJSON(os.Stdout, entities)
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ENTITY\tLEVEL\tLAYER\tX\tY\tSIZE\tIID")
+		for _, e := range entities {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%dx%d\t%s\n",
+				e.Identifier, e.Level, e.Layer, e.X, e.Y, e.Width, e.Height, e.Iid)
+		}
+
+		return tw.Flush()
+	},
+}
+
+func init() {
+	entitiesCmd.Flags().StringArrayVar(&entityFilters, "filter", nil, "only list entities matching key=pattern (repeatable)")
+	rootCmd.AddCommand(entitiesCmd)
+}
+
+func newEntityInfo(lvl goldtk.Level, lyr goldtk.Layer, e goldtk.Entity) entityInfo {
+	pos := e.LevelPos()
+	info := entityInfo{
+		Identifier: e.Identifier(),
+		Iid:        e.Iid(),
+		Level:      lvl.Identifier(),
+		Layer:      lyr.Identifier(),
+		Tags:       e.Tags(),
+		X:          pos.X,
+		Y:          pos.Y,
+		Width:      e.Width(),
+		Height:     e.Height(),
+		Fields:     make(map[string]any),
+	}
+	for _, f := range e.Fields() {
+		info.Fields[string(f.Identifier())] = goldtk.FieldJSON(f)
+	}
+
+	return info
+}
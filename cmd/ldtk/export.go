@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"goldtk"
+	"goldtk/quicktype"
+	"goldtk/simple"
+	"goldtk/tiled"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var exportFlags struct {
+	out    string
+	format string
+}
+
+// levelExport is the JSON export of a level.
+type levelExport struct {
+	levelInfo
+	Entities []entityInfo `json:"entities"`
+}
+
+// exporters write a project to a directory, by format name.
+var exporters = map[string]func(r goldtk.Root, fsys goldtk.WriteFS) error{
+	"png":    exportPNG,
+	"json":   exportJSON,
+	"tiled":  tiled.Export,
+	"simple": simple.Export,
+	"images": exportImages,
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export <project>",
+	Short: "Export every level of a project",
+	Long: `Export every level of a project to a directory, one file per level:
+
+  png     the rendered level
+  json    the level properties and its entities with their fields
+  tiled   a Tiled map, along with Tiled tilesets
+  simple  the LDtk super simple export, a folder per level
+  images  the images of the project image export mode, or of every layer
+          and level when the project does not export images`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		export, ok := exporters[exportFlags.format]
+		if !ok {
+			return fmt.Errorf("unknown export format %s", exportFlags.format)
+		}
+
+		r, err := load(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(exportFlags.out, 0o755); err != nil {
+			return err
+		}
+
+		return export(r, goldtk.DirWriteFS(exportFlags.out))
+	},
+}
+
+func init() {
+	f := exportCmd.Flags()
+	f.StringVarP(&exportFlags.out, "out", "o", ".", "output directory")
+	f.StringVarP(&exportFlags.format, "format", "f", "png", "export format")
+	addRenderFlags(exportCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+func exportPNG(r goldtk.Root, fsys goldtk.WriteFS) error {
+	if err := goldtk.CheckLevelIdentifiers(r); err != nil {
+		return err
+	}
+
+	renderer := goldtk.NewRenderer(r, renderOptions())
+	for _, lvl := range r.Levels() {
+		name := string(lvl.Identifier()) + ".png"
+		if err := goldtk.WriteImage(fsys, name, renderer.RenderLevel(lvl)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func exportImages(r goldtk.Root, fsys goldtk.WriteFS) error {
+	mode := r.ImageExportMode()
+	if mode == quicktype.ImageExportModeNone {
+		mode = quicktype.LayersAndLevels
+	}
+
+	return goldtk.ExportImages(r, fsys, mode)
+}
+
+func exportJSON(r goldtk.Root, fsys goldtk.WriteFS) error {
+	if err := goldtk.CheckLevelIdentifiers(r); err != nil {
+		return err
+	}
+
+	for _, w := range r.Worlds() {
+		for _, lvl := range w.Levels() {
+			export := levelExport{
+				levelInfo: newLevelInfo(w, lvl),
+				Entities:  make([]entityInfo, 0),
+			}
+			for _, lyr := range lvl.Layers() {
+				for _, e := range lyr.Entities() {
+					export.Entities = append(export.Entities, newEntityInfo(lvl, lyr, e))
+				}
+			}
+
+			data, err := json.MarshalIndent(export, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			name := string(lvl.Identifier()) + ".json"
+			if err := fsys.WriteFile(name, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"goldtk/ldtkjson"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	fmtWrite  bool
+	fmtList   bool
+	fmtMinify bool
+	fmtIndent bool
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt <file>...",
+	Short: "Rewrite projects and level files in a canonical form",
+	Long: `Rewrite projects and external level files in a canonical form: object keys
+in the order the editor writes them, numbers in their shortest form, and
+either minified or indented by two spaces. Projects follow their "minifyJson"
+setting, and level files keep their style, unless --minify or --indent is
+given. Files saved by the editor are already formatted, unless an editor
+build writes them differently.
+
+The canonical form is printed, unless -w or -l is given. With -l, the command
+exits with a non-zero status when a file is not formatted, so that it works as
+a pre-commit check:
+
+  ldtk fmt -l $(git diff --cached --name-only -- '*.ldtk' '*.ldtkl')`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if fmtMinify && fmtIndent {
+			return fmt.Errorf("--minify and --indent are exclusive")
+		}
+
+		unformatted := 0
+		for _, name := range args {
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return err
+			}
+
+			out, err := ldtkjson.Format(data, minified(data))
+			if err != nil {
+				return fmt.Errorf("formatting %s: %w", name, err)
+			}
+
+			switch {
+			case fmtList:
+				if !bytes.Equal(data, out) {
+					fmt.Println(name)
+					unformatted++
+				}
+			case fmtWrite:
+				if bytes.Equal(data, out) {
+					continue
+				}
+				if err := os.WriteFile(name, out, 0o644); err != nil {
+					return err
+				}
+			default:
+				if _, err := os.Stdout.Write(out); err != nil {
+					return err
+				}
+			}
+		}
+
+		if unformatted > 0 {
+			return fmt.Errorf("%d files not formatted", unformatted)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	fmtCmd.Flags().BoolVarP(&fmtWrite, "write", "w", false, "write the result to the files")
+	fmtCmd.Flags().BoolVarP(&fmtList, "list", "l", false, "list the files whose formatting differs")
+	fmtCmd.Flags().BoolVar(&fmtMinify, "minify", false, "minify the files")
+	fmtCmd.Flags().BoolVar(&fmtIndent, "indent", false, "indent the files")
+	rootCmd.AddCommand(fmtCmd)
+}
+
+// minified returns whether a file is to be minified: from the flags, or as
+// the file is written, see ldtkjson.Minified.
+func minified(data []byte) bool {
+	switch {
+	case fmtMinify:
+		return true
+	case fmtIndent:
+		return false
+	}
+
+	return ldtkjson.Minified(data)
+}
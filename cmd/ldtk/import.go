@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"goldtk/tiled"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var importFlags struct {
+	out string
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <map.tmx>",
+	Short: "Convert a Tiled map to a project",
+	Long: `Convert a Tiled map to a project holding a single level.
+
+The project is written next to the map by default. Paths in the project, such as
+tileset images, are rewritten when it is written to another directory.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+
+		project, err := tiled.Import(os.DirFS(filepath.Dir(name)), filepath.Base(name))
+		if err != nil {
+			return err
+		}
+
+		out := importFlags.out
+		if out == "" {
+			out = strings.TrimSuffix(name, filepath.Ext(name)) + ".ldtk"
+		}
+		out, err = filepath.Abs(out)
+		if err != nil {
+			return err
+		}
+
+		if filepath.Dir(out) != filepath.Dir(name) {
+			rel, err := filepath.Rel(filepath.Dir(out), filepath.Dir(name))
+			if err != nil {
+				return fmt.Errorf("locating %s from %s: %w", name, out, err)
+			}
+			tiled.RebasePaths(&project, filepath.ToSlash(rel))
+		}
+
+		data, err := json.MarshalIndent(project, "", "\t")
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(out, data, 0o644)
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVarP(&importFlags.out, "out", "o", "", "output project, defaults to the map with an .ldtk extension")
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"goldtk"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+type worldInfo struct {
+	Identifier goldtk.Identifier         `json:"identifier"`
+	Iid        goldtk.InstanceIdentifier `json:"iid"`
+	Layout     goldtk.WorldLayout        `json:"layout"`
+	Levels     int                       `json:"levels"`
+	Layers     int                       `json:"layers"`
+	Entities   int                       `json:"entities"`
+}
+
+type projectInfo struct {
+	Iid      goldtk.InstanceIdentifier `json:"iid"`
+	Tilesets int                       `json:"tilesets"`
+	Levels   int                       `json:"levels"`
+	Layers   int                       `json:"layers"`
+	Entities int                       `json:"entities"`
+	Worlds   []worldInfo               `json:"worlds"`
+
+	// Migrations lists the changes made to load a project saved by an older
+	// version of the editor.
+	Migrations []string `json:"migrations"`
+}
+
+var infoCmd = &cobra.Command{
+	Use:   "info <project>",
+	Short: "Print the number of worlds, levels, layers and entities",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, err := load(args[0])
+		if err != nil {
+			return err
+		}
+
+		info := projectInfo{
+			Iid:      r.Iid(),
+			Tilesets: len(r.Tilesets()),
+			Worlds:   make([]worldInfo, 0),
+		}
+		for _, w := range r.Worlds() {
+			wi := worldInfo{
+				Identifier: w.Identifier(),
+				Iid:        w.Iid(),
+				Layout:     w.Layout(),
+				Levels:     len(w.Levels()),
+			}
+			for _, lvl := range w.Levels() {
+				for _, lyr := range lvl.Layers() {
+					wi.Layers++
+					wi.Entities += len(lyr.Entities())
+				}
+			}
+
+			info.Levels += wi.Levels
+			info.Layers += wi.Layers
+			info.Entities += wi.Entities
+			info.Worlds = append(info.Worlds, wi)
+		}
+
+		// Listed once every level is loaded, so that external levels are
+		// migrated.
+		info.Migrations = make([]string, 0)
+		for _, m := range r.Migrations() {
+			info.Migrations = append(info.Migrations, m.String())
+		}
+
+		if jsonOutput {
+			return printJSON(os.Stdout, info)
+		}
+
+		fmt.Printf("project  %s\n", info.Iid)
+		fmt.Printf("tilesets %d\n", info.Tilesets)
+		fmt.Printf("worlds   %d\n", len(info.Worlds))
+		fmt.Printf("levels   %d\n", info.Levels)
+		fmt.Printf("layers   %d\n", info.Layers)
+		fmt.Printf("entities %d\n\n", info.Entities)
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "WORLD\tLAYOUT\tLEVELS\tLAYERS\tENTITIES")
+		for _, w := range info.Worlds {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\n", w.Identifier, w.Layout, w.Levels, w.Layers, w.Entities)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		if len(info.Migrations) > 0 {
+			fmt.Println("\nmigrations")
+			for _, m := range info.Migrations {
+				fmt.Println("  " + m)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"goldtk"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+type levelInfo struct {
+	Identifier goldtk.Identifier         `json:"identifier"`
+	Iid        goldtk.InstanceIdentifier `json:"iid"`
+	World      goldtk.Identifier         `json:"world"`
+	WorldX     int                       `json:"worldX"`
+	WorldY     int                       `json:"worldY"`
+	WorldDepth int                       `json:"worldDepth"`
+	PxWidth    int                       `json:"pxWid"`
+	PxHeight   int                       `json:"pxHei"`
+	Layers     int                       `json:"layers"`
+	External   string                    `json:"externalRelPath,omitempty"`
+}
+
+var levelsCmd = &cobra.Command{
+	Use:   "levels <project>",
+	Short: "List the levels of every world",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, err := load(args[0])
+		if err != nil {
+			return err
+		}
+
+		levels := make([]levelInfo, 0)
+		for _, w := range r.Worlds() {
+			for _, lvl := range w.Levels() {
+				levels = append(levels, newLevelInfo(w, lvl))
+			}
+		}
+
+		if jsonOutput {
+			return printJSON(os.Stdout, levels)
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "LEVEL\tWORLD\tX\tY\tDEPTH\tSIZE\tLAYERS\tIID")
+		for _, l := range levels {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%dx%d\t%d\t%s\n",
+				l.Identifier, l.World, l.WorldX, l.WorldY, l.WorldDepth, l.PxWidth, l.PxHeight, l.Layers, l.Iid)
+		}
+
+		return tw.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(levelsCmd)
+}
+
+func newLevelInfo(w goldtk.World, lvl goldtk.Level) levelInfo {
+	external, _ := lvl.ExternalRelPath().Get()
+
+	return levelInfo{
+		Identifier: lvl.Identifier(),
+		Iid:        lvl.Iid(),
+		World:      w.Identifier(),
+		WorldX:     lvl.WorldX(),
+		WorldY:     lvl.WorldY(),
+		WorldDepth: lvl.WorldDepth(),
+		PxWidth:    lvl.PxWidth(),
+		PxHeight:   lvl.PxHeight(),
+		Layers:     len(lvl.Layers()),
+		External:   external,
+	}
+}
@@ -0,0 +1,69 @@
+// Command ldtk inspects and operates on LDtk projects.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"goldtk"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var jsonOutput bool
+
+var rootCmd = &cobra.Command{
+	Use:           "ldtk",
+	Short:         "Inspect and operate on LDtk projects",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "print JSON instead of text")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "ldtk:", err)
+		os.Exit(1)
+	}
+}
+
+// load opens a project from the file system. The whole file system is exposed
+// to the project, as tileset paths may point above the project directory.
+func load(name string) (goldtk.Root, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return nil, err
+	}
+
+	vol := filepath.VolumeName(abs)
+	sys := goldtk.DirFS(vol + string(filepath.Separator))
+	rel := strings.TrimPrefix(filepath.ToSlash(abs[len(vol):]), "/")
+
+	return goldtk.Load(sys, rel)
+}
+
+// printJSON writes v as indented JSON.
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// findLevel returns the level with the given identifier or instance
+// identifier.
+func findLevel(r goldtk.Root, name string) (goldtk.Level, error) {
+	for _, lvl := range r.Levels() {
+		if string(lvl.Identifier()) == name || string(lvl.Iid()) == name {
+			return lvl, nil
+		}
+	}
+
+	return nil, fmt.Errorf("level %s not found", name)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"goldtk/merge"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+var mergeOutput string
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <base> <ours> <theirs>",
+	Short: "Merge two versions of a project edited from a common ancestor",
+	Long: `Merge the changes from base to ours and from base to theirs, matching levels,
+layers and entities by IID and definitions by UID. Definitions created on both
+sides with the same UID are given new UIDs on their side.
+
+The merged file is written over ours, unless an output is given. Values
+changed differently by both sides keep our version, are reported, and make the
+command exit with a non-zero status. Project and external level files can be
+merged, so that the command works as a git merge driver:
+
+  git config merge.ldtk.name "LDtk project merge"
+  git config merge.ldtk.driver "ldtk merge %O %A %B"
+  printf '*.ldtk merge=ldtk\n*.ldtkl merge=ldtk\n' >> .gitattributes`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files := make([][]byte, 0, len(args))
+		for _, name := range args {
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return err
+			}
+			files = append(files, data)
+		}
+
+		result, err := merge.Merge(files[0], files[1], files[2])
+		if err != nil {
+			return err
+		}
+
+		output := mergeOutput
+		if output == "" {
+			output = args[1]
+		}
+		if err := os.WriteFile(output, result.Data, 0o644); err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			if err := printJSON(os.Stdout, result.Conflicts); err != nil {
+				return err
+			}
+		} else {
+			uids := make([]int64, 0, len(result.Reallocated))
+			for uid := range result.Reallocated {
+				uids = append(uids, uid)
+			}
+			slices.Sort(uids)
+			for _, uid := range uids {
+				fmt.Fprintf(os.Stderr, "reallocated their uid %d to %d\n", uid, result.Reallocated[uid])
+			}
+
+			for _, c := range result.Conflicts {
+				fmt.Fprintln(os.Stderr, "conflict", c)
+			}
+		}
+
+		if len(result.Conflicts) > 0 {
+			return fmt.Errorf("%d conflicts, our version was kept", len(result.Conflicts))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	mergeCmd.Flags().StringVarP(&mergeOutput, "output", "o", "", "output file, ours by default")
+	rootCmd.AddCommand(mergeCmd)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"goldtk"
+	"image"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var renderFlags struct {
+	out        string
+	layer      string
+	noBg       bool
+	noIntGrid  bool
+	noEntities bool
+	hidden     bool
+}
+
+var renderCmd = &cobra.Command{
+	Use:   "render <project> <level>",
+	Short: "Render a level to a PNG image",
+	Long: `Render a level, given by identifier or instance identifier, to a PNG image.
+The image is written to <level>.png unless --out is set.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, err := load(args[0])
+		if err != nil {
+			return err
+		}
+
+		lvl, err := findLevel(r, args[1])
+		if err != nil {
+			return err
+		}
+
+		renderer := goldtk.NewRenderer(r, renderOptions())
+
+		var img image.Image
+		if renderFlags.layer == "" {
+			img = renderer.RenderLevel(lvl)
+		} else {
+			lyr, err := findLayer(lvl, renderFlags.layer)
+			if err != nil {
+				return err
+			}
+			img = renderer.RenderLayer(lvl, lyr)
+		}
+
+		out := renderFlags.out
+		if out == "" {
+			out = string(lvl.Identifier()) + ".png"
+		}
+
+		return writeFile(out, img)
+	},
+}
+
+func init() {
+	f := renderCmd.Flags()
+	f.StringVarP(&renderFlags.out, "out", "o", "", "output file")
+	f.StringVar(&renderFlags.layer, "layer", "", "only render the layer with this identifier")
+	addRenderFlags(renderCmd)
+	rootCmd.AddCommand(renderCmd)
+}
+
+// addRenderFlags registers the flags selecting what levels show.
+func addRenderFlags(cmd *cobra.Command) {
+	f := cmd.Flags()
+	f.BoolVar(&renderFlags.noBg, "no-bg", false, "leave the level background transparent")
+	f.BoolVar(&renderFlags.noIntGrid, "no-intgrid", false, "do not draw IntGrid cell colors")
+	f.BoolVar(&renderFlags.noEntities, "no-entities", false, "do not draw entities")
+	f.BoolVar(&renderFlags.hidden, "hidden", false, "also draw hidden layers")
+}
+
+func renderOptions() goldtk.RenderOptions {
+	return goldtk.RenderOptions{
+		Background: !renderFlags.noBg,
+		IntGrid:    !renderFlags.noIntGrid,
+		Entities:   !renderFlags.noEntities,
+		Hidden:     renderFlags.hidden,
+	}
+}
+
+func findLayer(lvl goldtk.Level, name string) (goldtk.Layer, error) {
+	for _, lyr := range lvl.Layers() {
+		if string(lyr.Identifier()) == name || string(lyr.Iid()) == name {
+			return lyr, nil
+		}
+	}
+
+	return nil, fmt.Errorf("layer %s not found in level %s", name, lvl.Identifier())
+}
+
+// writeFile writes img as PNG to the path name.
+func writeFile(name string, img image.Image) error {
+	return goldtk.WriteImage(goldtk.DirWriteFS(filepath.Dir(name)), filepath.Base(name), img)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"goldtk"
+	"goldtk/ansi"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+var showFlags struct {
+	opts   ansi.Options
+	glyphs []string
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show <project> <level>",
+	Short: "Draw a level in the terminal",
+	Long: `Draw a level, given by identifier or instance identifier, in the terminal.
+
+IntGrid cells are drawn with the color of their value, and entities with the
+first letter of their identifier in the color of their definition. Glyphs are
+overridden with --glyph name=c, where name is an entity identifier, an IntGrid
+value identifier, or an IntGrid value number.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := showFlags.opts
+		opts.EntityGlyphs = make(map[goldtk.Identifier]rune)
+		opts.IntGridGlyphs = make(map[string]rune)
+		for _, s := range showFlags.glyphs {
+			name, glyph, ok := strings.Cut(s, "=")
+			if !ok || utf8.RuneCountInString(glyph) != 1 {
+				return fmt.Errorf("glyph %q is not name=c", s)
+			}
+
+			r, _ := utf8.DecodeRuneInString(glyph)
+			opts.EntityGlyphs[goldtk.Identifier(name)] = r
+			opts.IntGridGlyphs[name] = r
+		}
+
+		r, err := load(args[0])
+		if err != nil {
+			return err
+		}
+
+		lvl, err := findLevel(r, args[1])
+		if err != nil {
+			return err
+		}
+
+		return ansi.Render(os.Stdout, lvl, opts)
+	},
+}
+
+func init() {
+	f := showCmd.Flags()
+	f.IntVarP(&showFlags.opts.Viewport.CX, "x", "x", 0, "first column to show, in cells")
+	f.IntVarP(&showFlags.opts.Viewport.CY, "y", "y", 0, "first row to show, in cells")
+	f.IntVarP(&showFlags.opts.Viewport.W, "width", "W", 0, "number of columns to show, 0 for all")
+	f.IntVarP(&showFlags.opts.Viewport.H, "height", "H", 0, "number of rows to show, 0 for all")
+	f.IntVar(&showFlags.opts.CellSize, "cell-size", 0, "pixels per cell, defaults to the smallest layer grid size")
+	f.StringArrayVar(&showFlags.glyphs, "glyph", nil, "glyph override as name=c (repeatable)")
+	f.BoolVar(&showFlags.opts.Wide, "wide", false, "print every cell twice")
+	f.BoolVar(&showFlags.opts.NoColor, "no-color", false, "do not print ANSI colors")
+	rootCmd.AddCommand(showCmd)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"goldtk"
+	"goldtk/lint"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// problem is a finding of the validate command, from any of its sources.
+type problem struct {
+	Source   string        `json:"source"`
+	Severity lint.Severity `json:"severity"`
+	File     string        `json:"file,omitempty"`
+	Location string        `json:"location,omitempty"`
+	Message  string        `json:"message"`
+}
+
+func (p problem) String() string {
+	s := fmt.Sprintf("%s [%s]", p.Severity, p.Source)
+	if p.File != "" {
+		s += " " + p.File
+	}
+	if p.Location != "" {
+		s += " " + p.Location
+	}
+
+	return s + ": " + p.Message
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <project>",
+	Short: "Check a project against the JSON schema, lint checks and entity references",
+	Long: `Check a project against the LDtk JSON schema, including its external level
+files, then run every lint check and validate entity references.
+
+The command exits with a non-zero status when an error is found.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		problems, err := schemaProblems(name, name)
+		if err != nil {
+			return err
+		}
+
+		// A project which fails to load still reports its schema problems,
+		// which usually explain the failure. Lint and reference checks need
+		// the loaded project and are skipped.
+		r, err := load(name)
+		if err != nil {
+			problems = append(problems, problem{
+				Source:   "load",
+				Severity: lint.Error,
+				File:     name,
+				Message:  err.Error(),
+			})
+		} else {
+			problems = append(problems, projectProblems(r, name)...)
+		}
+
+		if jsonOutput {
+			if err := printJSON(os.Stdout, problems); err != nil {
+				return err
+			}
+		} else {
+			for _, p := range problems {
+				fmt.Println(p)
+			}
+		}
+
+		errors := 0
+		for _, p := range problems {
+			if p.Severity == lint.Error {
+				errors++
+			}
+		}
+		if errors > 0 {
+			return fmt.Errorf("%d errors found", errors)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+// projectProblems validates the external level files of the project name
+// against the JSON schema, then runs the lint checks and validates entity
+// references.
+func projectProblems(r goldtk.Root, name string) []problem {
+	problems := make([]problem, 0)
+	for _, lvl := range r.Levels() {
+		rel, ok := lvl.ExternalRelPath().Get()
+		if !ok {
+			continue
+		}
+
+		found, err := schemaProblems(filepath.Join(filepath.Dir(name), filepath.FromSlash(rel)), rel)
+		if err != nil {
+			problems = append(problems, problem{
+				Source:   "load",
+				Severity: lint.Error,
+				File:     rel,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		problems = append(problems, found...)
+	}
+
+	for _, d := range lint.Run(r) {
+		problems = append(problems, problem{
+			Source:   d.Check,
+			Severity: d.Severity,
+			Location: d.Location.String(),
+			Message:  d.Message,
+		})
+	}
+
+	for _, p := range goldtk.NewReferenceGraph(r).Validate() {
+		severity := lint.Error
+		if p.Kind == goldtk.ReferenceCycle {
+			severity = lint.Warning
+		}
+
+		problems = append(problems, problem{
+			Source:   string(p.Kind),
+			Severity: severity,
+			Message:  p.Message,
+		})
+	}
+
+	return problems
+}
+
+// schemaProblems validates the file name against the JSON schema, reporting
+// it as file.
+func schemaProblems(name, file string) ([]problem, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := make([]problem, 0)
+	for _, e := range goldtk.ValidateSchema(data) {
+		problems = append(problems, problem{
+			Source:   "schema",
+			Severity: lint.Error,
+			File:     file,
+			Location: e.Pointer,
+			Message:  e.Message,
+		})
+	}
+
+	return problems, nil
+}
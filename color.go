@@ -76,18 +76,18 @@ type clr struct {
 }
 
 func (c clr) Hex() string {
-	r, g, b, _ := c.value.RGBA()
-	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	rgba := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", rgba.R, rgba.G, rgba.B)
 }
 
 func (c clr) RGBA() color.RGBA {
 	r, g, b, a := c.value.RGBA()
 
 	return color.RGBA{
-		R: uint8(r),
-		G: uint8(g),
-		B: uint8(b),
-		A: uint8(a),
+		R: uint8(r >> 8),
+		G: uint8(g >> 8),
+		B: uint8(b >> 8),
+		A: uint8(a >> 8),
 	}
 }
 
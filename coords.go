@@ -0,0 +1,229 @@
+package goldtk
+
+import "math"
+
+// LDtk positions are expressed in one of four coordinate spaces:
+//
+//   - world space: pixels relative to the world origin.
+//   - level space: pixels relative to the top-left corner of a level.
+//   - layer space: pixels relative to the origin of a layer, which is the level
+//     origin moved by the layer total offset (`__pxTotalOffsetX/Y`). Entity
+//     `px` values and tile `px` values are in layer space.
+//   - grid space: cell coordinates of a layer grid, `GridSize` pixels wide.
+//
+// Every space has its own point and rectangle types so that values from
+// different spaces cannot be mixed up by accident. LayerSpace converts values
+// between them.
+
+// WorldPoint is a pixel position in world space.
+type WorldPoint struct {
+	X, Y int
+}
+
+// LevelPoint is a pixel position relative to the top-left corner of a level.
+type LevelPoint struct {
+	X, Y int
+}
+
+// LayerPoint is a pixel position relative to the origin of a layer.
+type LayerPoint struct {
+	X, Y int
+}
+
+// GridPoint is the position of a cell in a layer grid.
+type GridPoint struct {
+	CX, CY int
+}
+
+// WorldRect is an axis-aligned rectangle in world pixel coordinates.
+type WorldRect struct {
+	X, Y int
+	W, H int
+}
+
+// Contains returns true if the world pixel x, y is inside the rectangle.
+func (r WorldRect) Contains(x, y int) bool {
+	return contains(r.X, r.Y, r.W, r.H, x, y)
+}
+
+// Overlaps returns true if both rectangles share at least one pixel.
+func (r WorldRect) Overlaps(o WorldRect) bool {
+	return overlaps(r.X, r.Y, r.W, r.H, o.X, o.Y, o.W, o.H)
+}
+
+// LevelRect is an axis-aligned rectangle in level pixel coordinates.
+type LevelRect struct {
+	X, Y int
+	W, H int
+}
+
+// Contains returns true if the level pixel x, y is inside the rectangle.
+func (r LevelRect) Contains(x, y int) bool {
+	return contains(r.X, r.Y, r.W, r.H, x, y)
+}
+
+// Overlaps returns true if both rectangles share at least one pixel.
+func (r LevelRect) Overlaps(o LevelRect) bool {
+	return overlaps(r.X, r.Y, r.W, r.H, o.X, o.Y, o.W, o.H)
+}
+
+// LayerRect is an axis-aligned rectangle in layer pixel coordinates.
+type LayerRect struct {
+	X, Y int
+	W, H int
+}
+
+// Contains returns true if the layer pixel x, y is inside the rectangle.
+func (r LayerRect) Contains(x, y int) bool {
+	return contains(r.X, r.Y, r.W, r.H, x, y)
+}
+
+// Overlaps returns true if both rectangles share at least one pixel.
+func (r LayerRect) Overlaps(o LayerRect) bool {
+	return overlaps(r.X, r.Y, r.W, r.H, o.X, o.Y, o.W, o.H)
+}
+
+// GridRect is a block of cells in a layer grid. W and H are counted in cells.
+type GridRect struct {
+	CX, CY int
+	W, H   int
+}
+
+// Contains returns true if the cell cx, cy is inside the rectangle.
+func (r GridRect) Contains(cx, cy int) bool {
+	return contains(r.CX, r.CY, r.W, r.H, cx, cy)
+}
+
+// Overlaps returns true if both rectangles share at least one cell.
+func (r GridRect) Overlaps(o GridRect) bool {
+	return overlaps(r.CX, r.CY, r.W, r.H, o.CX, o.CY, o.W, o.H)
+}
+
+// LayerSpace holds everything needed to convert between the coordinate spaces
+// of a single layer instance.
+type LayerSpace struct {
+	// LevelWorldX and LevelWorldY are the world coordinates of the level.
+	LevelWorldX, LevelWorldY int
+
+	// OffsetX and OffsetY are the total pixel offset of the layer
+	// (`__pxTotalOffsetX/Y`).
+	OffsetX, OffsetY int
+
+	// GridSize is the size of a layer cell in pixels.
+	GridSize int
+}
+
+// LevelToWorld converts a level position to world space.
+func (s LayerSpace) LevelToWorld(p LevelPoint) WorldPoint {
+	return WorldPoint{X: p.X + s.LevelWorldX, Y: p.Y + s.LevelWorldY}
+}
+
+// WorldToLevel converts a world position to level space.
+func (s LayerSpace) WorldToLevel(p WorldPoint) LevelPoint {
+	return LevelPoint{X: p.X - s.LevelWorldX, Y: p.Y - s.LevelWorldY}
+}
+
+// LayerToLevel converts a layer position to level space.
+func (s LayerSpace) LayerToLevel(p LayerPoint) LevelPoint {
+	return LevelPoint{X: p.X + s.OffsetX, Y: p.Y + s.OffsetY}
+}
+
+// LevelToLayer converts a level position to layer space.
+func (s LayerSpace) LevelToLayer(p LevelPoint) LayerPoint {
+	return LayerPoint{X: p.X - s.OffsetX, Y: p.Y - s.OffsetY}
+}
+
+// LayerToWorld converts a layer position to world space.
+func (s LayerSpace) LayerToWorld(p LayerPoint) WorldPoint {
+	return s.LevelToWorld(s.LayerToLevel(p))
+}
+
+// WorldToLayer converts a world position to layer space.
+func (s LayerSpace) WorldToLayer(p WorldPoint) LayerPoint {
+	return s.LevelToLayer(s.WorldToLevel(p))
+}
+
+// LayerToGrid returns the cell containing the layer position. Positions left
+// of, or above, the layer origin map to negative cells.
+func (s LayerSpace) LayerToGrid(p LayerPoint) GridPoint {
+	if s.GridSize <= 0 {
+		return GridPoint{}
+	}
+
+	return GridPoint{CX: floorDiv(p.X, s.GridSize), CY: floorDiv(p.Y, s.GridSize)}
+}
+
+// GridToLayer returns the layer position of the top-left corner of the cell.
+func (s LayerSpace) GridToLayer(g GridPoint) LayerPoint {
+	return LayerPoint{X: g.CX * s.GridSize, Y: g.CY * s.GridSize}
+}
+
+// LevelToGrid returns the cell containing the level position.
+func (s LayerSpace) LevelToGrid(p LevelPoint) GridPoint {
+	return s.LayerToGrid(s.LevelToLayer(p))
+}
+
+// GridToLevel returns the level position of the top-left corner of the cell.
+func (s LayerSpace) GridToLevel(g GridPoint) LevelPoint {
+	return s.LayerToLevel(s.GridToLayer(g))
+}
+
+// WorldToGrid returns the cell containing the world position.
+func (s LayerSpace) WorldToGrid(p WorldPoint) GridPoint {
+	return s.LayerToGrid(s.WorldToLayer(p))
+}
+
+// GridToWorld returns the world position of the top-left corner of the cell.
+func (s LayerSpace) GridToWorld(g GridPoint) WorldPoint {
+	return s.LayerToWorld(s.GridToLayer(g))
+}
+
+// LayerRectToLevel moves a layer rectangle to level space.
+func (s LayerSpace) LayerRectToLevel(r LayerRect) LevelRect {
+	p := s.LayerToLevel(LayerPoint{X: r.X, Y: r.Y})
+	return LevelRect{X: p.X, Y: p.Y, W: r.W, H: r.H}
+}
+
+// LayerRectToWorld moves a layer rectangle to world space.
+func (s LayerSpace) LayerRectToWorld(r LayerRect) WorldRect {
+	p := s.LayerToWorld(LayerPoint{X: r.X, Y: r.Y})
+	return WorldRect{X: p.X, Y: p.Y, W: r.W, H: r.H}
+}
+
+// LayerRectToGrid returns the smallest block of cells covering the layer
+// rectangle. An empty rectangle covers no cells.
+func (s LayerSpace) LayerRectToGrid(r LayerRect) GridRect {
+	if r.W <= 0 || r.H <= 0 || s.GridSize <= 0 {
+		return GridRect{}
+	}
+
+	first := s.LayerToGrid(LayerPoint{X: r.X, Y: r.Y})
+	last := s.LayerToGrid(LayerPoint{X: r.X + r.W - 1, Y: r.Y + r.H - 1})
+
+	return GridRect{CX: first.CX, CY: first.CY, W: last.CX - first.CX + 1, H: last.CY - first.CY + 1}
+}
+
+// GridRectToLayer returns the layer pixels covered by the block of cells.
+func (s LayerSpace) GridRectToLayer(r GridRect) LayerRect {
+	p := s.GridToLayer(GridPoint{CX: r.CX, CY: r.CY})
+	return LayerRect{X: p.X, Y: p.Y, W: r.W * s.GridSize, H: r.H * s.GridSize}
+}
+
+// pivotRect returns the bounds of a w by h box whose pivot point, expressed as
+// fractions of its size, sits at x, y.
+func pivotRect(x, y, w, h int, pivotX, pivotY float64) LayerRect {
+	return LayerRect{
+		X: x - int(math.Round(pivotX*float64(w))),
+		Y: y - int(math.Round(pivotY*float64(h))),
+		W: w,
+		H: h,
+	}
+}
+
+func contains(rx, ry, rw, rh, x, y int) bool {
+	return x >= rx && x < rx+rw && y >= ry && y < ry+rh
+}
+
+func overlaps(ax, ay, aw, ah, bx, by, bw, bh int) bool {
+	return ax < bx+bw && bx < ax+aw && ay < by+bh && by < ay+ah
+}
@@ -0,0 +1,470 @@
+package goldtk
+
+import (
+	"goldtk/maybe"
+	"goldtk/quicktype"
+)
+
+// EntityDef is the definition shared by every instance of an entity.
+type EntityDef interface {
+	Identifier() Identifier
+	Uid() Uid
+	Tags() []string
+	Doc() maybe.Value[string]
+
+	Color() Color
+
+	Width() int
+	Height() int
+	Size() (width, height int)
+
+	// Pivot returns the default pivot of the entity, as fractions of its size.
+	Pivot() (x, y float64)
+
+	RenderMode() quicktype.RenderMode
+	TileRenderMode() quicktype.TileRenderMode
+	TileRect() maybe.Value[quicktype.TilesetRectangle]
+	TilesetUid() maybe.Value[int64]
+	FillOpacity() float64
+	LineOpacity() float64
+	Hollow() bool
+	ShowName() bool
+
+	ResizableX() bool
+	ResizableY() bool
+	KeepAspectRatio() bool
+
+	// MaxCount is the maximum number of instances in the LimitScope, or 0
+	// when unlimited.
+	MaxCount() int
+	LimitScope() quicktype.LimitScope
+	LimitBehavior() quicktype.LimitBehavior
+	AllowOutOfBounds() bool
+
+	ExportToToc() bool
+
+	Fields() []FieldDef
+}
+
+type entityDef struct {
+	def quicktype.EntityDefinition
+}
+
+func (e entityDef) Identifier() Identifier {
+	return Identifier(e.def.Identifier)
+}
+
+func (e entityDef) Uid() Uid {
+	return Uid(e.def.Uid)
+}
+
+func (e entityDef) Tags() []string {
+	return e.def.Tags
+}
+
+func (e entityDef) Doc() maybe.Value[string] {
+	return maybe.From[string](e.def.Doc)
+}
+
+func (e entityDef) Color() Color {
+	return ColorFromHex(e.def.Color)
+}
+
+func (e entityDef) Width() int {
+	return int(e.def.Width)
+}
+
+func (e entityDef) Height() int {
+	return int(e.def.Height)
+}
+
+func (e entityDef) Size() (width, height int) {
+	return e.Width(), e.Height()
+}
+
+func (e entityDef) Pivot() (x, y float64) {
+	return e.def.PivotX, e.def.PivotY
+}
+
+func (e entityDef) RenderMode() quicktype.RenderMode {
+	return e.def.RenderMode
+}
+
+func (e entityDef) TileRenderMode() quicktype.TileRenderMode {
+	return e.def.TileRenderMode
+}
+
+func (e entityDef) TileRect() maybe.Value[quicktype.TilesetRectangle] {
+	return maybe.From[quicktype.TilesetRectangle](e.def.TileRect)
+}
+
+func (e entityDef) TilesetUid() maybe.Value[int64] {
+	return maybe.From[int64](e.def.TilesetID)
+}
+
+func (e entityDef) FillOpacity() float64 {
+	return e.def.FillOpacity
+}
+
+func (e entityDef) LineOpacity() float64 {
+	return e.def.LineOpacity
+}
+
+func (e entityDef) Hollow() bool {
+	return e.def.Hollow
+}
+
+func (e entityDef) ShowName() bool {
+	return e.def.ShowName
+}
+
+func (e entityDef) ResizableX() bool {
+	return e.def.ResizableX
+}
+
+func (e entityDef) ResizableY() bool {
+	return e.def.ResizableY
+}
+
+func (e entityDef) KeepAspectRatio() bool {
+	return e.def.KeepAspectRatio
+}
+
+func (e entityDef) MaxCount() int {
+	return int(e.def.MaxCount)
+}
+
+func (e entityDef) LimitScope() quicktype.LimitScope {
+	return e.def.LimitScope
+}
+
+func (e entityDef) LimitBehavior() quicktype.LimitBehavior {
+	return e.def.LimitBehavior
+}
+
+func (e entityDef) AllowOutOfBounds() bool {
+	return e.def.AllowOutOfBounds
+}
+
+func (e entityDef) ExportToToc() bool {
+	return e.def.ExportToToc
+}
+
+func (e entityDef) Fields() []FieldDef {
+	fields := make([]FieldDef, 0)
+	for _, f := range e.def.FieldDefs {
+		fields = append(fields, NewFieldDef(f))
+	}
+
+	return fields
+}
+
+func NewEntityDef(def quicktype.EntityDefinition) EntityDef {
+	return entityDef{
+		def: def,
+	}
+}
+
+var _ EntityDef = entityDef{}
+
+// LayerDef is the definition shared by every instance of a layer.
+type LayerDef interface {
+	Identifier() Identifier
+	Uid() Uid
+	Type() LayerType
+	Doc() maybe.Value[string]
+	UIColor() maybe.Value[string]
+
+	GridSize() int
+	DisplayOpacity() float64
+	InactiveOpacity() float64
+
+	// PxOffset returns the offset of every instance of the layer, which is
+	// already included in the instance PxTotalOffsetX/Y.
+	PxOffset() (x, y int)
+
+	// Parallax returns the parallax factors of the layer, from -1 to 1.
+	Parallax() (x, y float64)
+	ParallaxScaling() bool
+
+	TilesetUid() maybe.Value[int64]
+	TilePivot() (x, y float64)
+
+	// IntGridValues returns the values an IntGrid layer cells can hold.
+	IntGridValues() []IntGridValue
+
+	// IntGridValue returns the definition of an IntGrid cell value.
+	IntGridValue(v int) (IntGridValue, bool)
+
+	AutoSourceLayerDefUid() maybe.Value[int64]
+	AutoRuleGroups() []quicktype.AutoLayerRuleGroup
+
+	RequiredTags() []string
+	ExcludedTags() []string
+}
+
+type layerDef struct {
+	def quicktype.LayerDefinition
+}
+
+func (l layerDef) Identifier() Identifier {
+	return Identifier(l.def.Identifier)
+}
+
+func (l layerDef) Uid() Uid {
+	return Uid(l.def.Uid)
+}
+
+func (l layerDef) Type() LayerType {
+	return LayerType(l.def.Type)
+}
+
+func (l layerDef) Doc() maybe.Value[string] {
+	return maybe.From[string](l.def.Doc)
+}
+
+func (l layerDef) UIColor() maybe.Value[string] {
+	return maybe.From[string](l.def.UIColor)
+}
+
+func (l layerDef) GridSize() int {
+	return int(l.def.GridSize)
+}
+
+func (l layerDef) DisplayOpacity() float64 {
+	return l.def.DisplayOpacity
+}
+
+func (l layerDef) InactiveOpacity() float64 {
+	return l.def.InactiveOpacity
+}
+
+func (l layerDef) PxOffset() (x, y int) {
+	return int(l.def.PxOffsetX), int(l.def.PxOffsetY)
+}
+
+func (l layerDef) Parallax() (x, y float64) {
+	return l.def.ParallaxFactorX, l.def.ParallaxFactorY
+}
+
+func (l layerDef) ParallaxScaling() bool {
+	return l.def.ParallaxScaling
+}
+
+func (l layerDef) TilesetUid() maybe.Value[int64] {
+	return maybe.From[int64](l.def.TilesetDefUid)
+}
+
+func (l layerDef) TilePivot() (x, y float64) {
+	return l.def.TilePivotX, l.def.TilePivotY
+}
+
+func (l layerDef) IntGridValues() []IntGridValue {
+	values := make([]IntGridValue, 0)
+	for _, v := range l.def.IntGridValues {
+		values = append(values, NewIntGridValue(v))
+	}
+
+	return values
+}
+
+func (l layerDef) IntGridValue(v int) (IntGridValue, bool) {
+	for _, def := range l.def.IntGridValues {
+		if int(def.Value) == v {
+			return NewIntGridValue(def), true
+		}
+	}
+
+	return nil, false
+}
+
+func (l layerDef) AutoSourceLayerDefUid() maybe.Value[int64] {
+	return maybe.From[int64](l.def.AutoSourceLayerDefUid)
+}
+
+func (l layerDef) AutoRuleGroups() []quicktype.AutoLayerRuleGroup {
+	return l.def.AutoRuleGroups
+}
+
+func (l layerDef) RequiredTags() []string {
+	return l.def.RequiredTags
+}
+
+func (l layerDef) ExcludedTags() []string {
+	return l.def.ExcludedTags
+}
+
+func NewLayerDef(def quicktype.LayerDefinition) LayerDef {
+	return layerDef{
+		def: def,
+	}
+}
+
+var _ LayerDef = layerDef{}
+
+// IntGridValue is one of the values an IntGrid layer cell can hold.
+type IntGridValue interface {
+	Value() int
+	Identifier() maybe.Value[string]
+	Color() Color
+	GroupUid() Uid
+	Tile() maybe.Value[quicktype.TilesetRectangle]
+}
+
+type intGridValue struct {
+	def quicktype.IntGridValueDefinition
+}
+
+func (v intGridValue) Value() int {
+	return int(v.def.Value)
+}
+
+func (v intGridValue) Identifier() maybe.Value[string] {
+	return maybe.From[string](v.def.Identifier)
+}
+
+func (v intGridValue) Color() Color {
+	return ColorFromHex(v.def.Color)
+}
+
+func (v intGridValue) GroupUid() Uid {
+	return Uid(v.def.GroupUid)
+}
+
+func (v intGridValue) Tile() maybe.Value[quicktype.TilesetRectangle] {
+	return maybe.From[quicktype.TilesetRectangle](v.def.Tile)
+}
+
+func NewIntGridValue(def quicktype.IntGridValueDefinition) IntGridValue {
+	return intGridValue{
+		def: def,
+	}
+}
+
+var _ IntGridValue = intGridValue{}
+
+// FieldDef is the definition of a field of an entity or a level.
+type FieldDef interface {
+	Identifier() Identifier
+	Uid() Uid
+	Doc() maybe.Value[string]
+
+	// Type returns the LDtk type of the field, eg. `Int` or `Array<Point>`.
+	Type() string
+	IsArray() bool
+	CanBeNull() bool
+	DefaultOverride() any
+
+	Min() maybe.Value[float64]
+	Max() maybe.Value[float64]
+	Regex() maybe.Value[string]
+	ArrayMinLength() maybe.Value[int64]
+	ArrayMaxLength() maybe.Value[int64]
+	AcceptFileTypes() []string
+
+	AllowedRefs() quicktype.AllowedRefs
+	AllowedRefsEntityUid() maybe.Value[int64]
+	AllowedRefTags() []string
+	AllowOutOfLevelRef() bool
+	SymmetricalRef() bool
+
+	TilesetUid() maybe.Value[int64]
+	ExportToToc() bool
+	Searchable() bool
+}
+
+type fieldDef struct {
+	def quicktype.FieldDefinition
+}
+
+func (f fieldDef) Identifier() Identifier {
+	return Identifier(f.def.Identifier)
+}
+
+func (f fieldDef) Uid() Uid {
+	return Uid(f.def.Uid)
+}
+
+func (f fieldDef) Doc() maybe.Value[string] {
+	return maybe.From[string](f.def.Doc)
+}
+
+func (f fieldDef) Type() string {
+	return f.def.Type
+}
+
+func (f fieldDef) IsArray() bool {
+	return f.def.IsArray
+}
+
+func (f fieldDef) CanBeNull() bool {
+	return f.def.CanBeNull
+}
+
+func (f fieldDef) DefaultOverride() any {
+	return f.def.DefaultOverride
+}
+
+func (f fieldDef) Min() maybe.Value[float64] {
+	return maybe.From[float64](f.def.Min)
+}
+
+func (f fieldDef) Max() maybe.Value[float64] {
+	return maybe.From[float64](f.def.Max)
+}
+
+func (f fieldDef) Regex() maybe.Value[string] {
+	return maybe.From[string](f.def.Regex)
+}
+
+func (f fieldDef) ArrayMinLength() maybe.Value[int64] {
+	return maybe.From[int64](f.def.ArrayMinLength)
+}
+
+func (f fieldDef) ArrayMaxLength() maybe.Value[int64] {
+	return maybe.From[int64](f.def.ArrayMaxLength)
+}
+
+func (f fieldDef) AcceptFileTypes() []string {
+	return f.def.AcceptFileTypes
+}
+
+func (f fieldDef) AllowedRefs() quicktype.AllowedRefs {
+	return f.def.AllowedRefs
+}
+
+func (f fieldDef) AllowedRefsEntityUid() maybe.Value[int64] {
+	return maybe.From[int64](f.def.AllowedRefsEntityUid)
+}
+
+func (f fieldDef) AllowedRefTags() []string {
+	return f.def.AllowedRefTags
+}
+
+func (f fieldDef) AllowOutOfLevelRef() bool {
+	return f.def.AllowOutOfLevelRef
+}
+
+func (f fieldDef) SymmetricalRef() bool {
+	return f.def.SymmetricalRef
+}
+
+func (f fieldDef) TilesetUid() maybe.Value[int64] {
+	return maybe.From[int64](f.def.TilesetUid)
+}
+
+func (f fieldDef) ExportToToc() bool {
+	return f.def.ExportToToc
+}
+
+func (f fieldDef) Searchable() bool {
+	return f.def.Searchable
+}
+
+func NewFieldDef(def quicktype.FieldDefinition) FieldDef {
+	return fieldDef{
+		def: def,
+	}
+}
+
+var _ FieldDef = fieldDef{}
@@ -0,0 +1,202 @@
+// Package diff compares two versions of an LDtk project, reporting what
+// changed in terms of levels, entities, fields, layers and definitions rather
+// than JSON lines. Instances are matched by their instance identifier, and
+// definitions by their unique identifier, so reordering arrays is not a
+// change.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"goldtk"
+	"goldtk/quicktype"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Kind is what happened to the subject of a Change.
+type Kind string
+
+const (
+	Added    Kind = "added"
+	Removed  Kind = "removed"
+	Moved    Kind = "moved"
+	Modified Kind = "modified"
+)
+
+// Subject is the part of a project a Change is about.
+type Subject string
+
+const (
+	Level   Subject = "level"
+	Layer   Subject = "layer"
+	Entity  Subject = "entity"
+	Field   Subject = "field"
+	IntGrid Subject = "intgrid"
+	Tiles   Subject = "tiles"
+
+	LayerDef      Subject = "layer-def"
+	EntityDef     Subject = "entity-def"
+	EnumDef       Subject = "enum-def"
+	TilesetDef    Subject = "tileset-def"
+	LevelFieldDef Subject = "level-field-def"
+)
+
+// Location points to the part of a project a Change is about, in the version
+// it belongs to: the new one, unless it was removed. Fields are left empty
+// when they do not apply.
+type Location struct {
+	World  goldtk.InstanceIdentifier
+	Level  goldtk.InstanceIdentifier
+	Layer  goldtk.InstanceIdentifier
+	Entity goldtk.InstanceIdentifier
+	Field  goldtk.Identifier
+
+	// Def is the unique identifier of a changed definition.
+	Def goldtk.Uid
+}
+
+func (l Location) String() string {
+	s := ""
+	add := func(name, value string) {
+		if value == "" {
+			return
+		}
+		if s != "" {
+			s += " "
+		}
+		s += name + "=" + value
+	}
+
+	add("world", string(l.World))
+	add("level", string(l.Level))
+	add("layer", string(l.Layer))
+	add("entity", string(l.Entity))
+	add("field", string(l.Field))
+	if l.Def != 0 {
+		add("def", fmt.Sprint(l.Def))
+	}
+
+	return s
+}
+
+// Change is a single difference between two versions of a project.
+type Change struct {
+	Kind     Kind
+	Subject  Subject
+	Location Location
+	Message  string
+
+	// Before and After are the JSON values of a changed field, and the
+	// positions of a moved level or entity.
+	Before any `json:",omitempty"`
+	After  any `json:",omitempty"`
+
+	// Cells are the layer grid cells of IntGrid and tile changes.
+	Cells []goldtk.GridPoint `json:",omitempty"`
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s %s: %s", c.Kind, c.Subject, c.Location, c.Message)
+}
+
+// Compare returns the changes from the project a to the project b:
+// definitions first, then levels and their content in the order of b, and
+// removals last.
+func Compare(a, b goldtk.Root) []Change {
+	changes := make([]Change, 0)
+
+	da, db := a.Defs(), b.Defs()
+	changes = append(changes, compareDefs(LayerDef, da.Layers, db.Layers, func(d quicktype.LayerDefinition) (int64, string) {
+		return d.Uid, d.Identifier
+	})...)
+	changes = append(changes, compareDefs(EntityDef, da.Entities, db.Entities, func(d quicktype.EntityDefinition) (int64, string) {
+		return d.Uid, d.Identifier
+	})...)
+	changes = append(changes, compareDefs(EnumDef, da.Enums, db.Enums, func(d quicktype.EnumDefinition) (int64, string) {
+		return d.Uid, d.Identifier
+	})...)
+	changes = append(changes, compareDefs(TilesetDef, da.Tilesets, db.Tilesets, func(d quicktype.TilesetDefinition) (int64, string) {
+		return d.Uid, d.Identifier
+	})...)
+	changes = append(changes, compareDefs(LevelFieldDef, da.LevelFields, db.LevelFields, func(d quicktype.FieldDefinition) (int64, string) {
+		return d.Uid, d.Identifier
+	})...)
+
+	changes = append(changes, compareLevels(a, b)...)
+	changes = append(changes, compareEntities(a, b)...)
+
+	return changes
+}
+
+// compareDefs matches the definitions of a and b by unique identifier. key
+// returns the unique identifier and the identifier of a definition.
+func compareDefs[D any](subject Subject, a, b []D, key func(D) (int64, string)) []Change {
+	changes := make([]Change, 0)
+
+	before := make(map[int64]D, len(a))
+	for _, def := range a {
+		uid, _ := key(def)
+		before[uid] = def
+	}
+
+	after := make(map[int64]bool, len(b))
+	for _, def := range b {
+		uid, id := key(def)
+		after[uid] = true
+		loc := Location{Def: goldtk.Uid(uid)}
+
+		old, ok := before[uid]
+		if !ok {
+			changes = append(changes, Change{Kind: Added, Subject: subject, Location: loc, Message: id + " added"})
+			continue
+		}
+		if reflect.DeepEqual(old, def) {
+			continue
+		}
+
+		_, oldID := key(old)
+		msg := id + " changed: " + strings.Join(changedKeys(old, def), ", ")
+		if oldID != id {
+			msg = fmt.Sprintf("%s renamed to %s, changed: %s", oldID, id, strings.Join(changedKeys(old, def), ", "))
+		}
+		changes = append(changes, Change{Kind: Modified, Subject: subject, Location: loc, Message: msg})
+	}
+
+	for _, def := range a {
+		if uid, id := key(def); !after[uid] {
+			loc := Location{Def: goldtk.Uid(uid)}
+			changes = append(changes, Change{Kind: Removed, Subject: subject, Location: loc, Message: id + " removed"})
+		}
+	}
+
+	return changes
+}
+
+// changedKeys returns the JSON keys whose value differs between a and b, in
+// order.
+func changedKeys(a, b any) []string {
+	var ma, mb map[string]any
+	if data, err := json.Marshal(a); err == nil {
+		_ = json.Unmarshal(data, &ma)
+	}
+	if data, err := json.Marshal(b); err == nil {
+		_ = json.Unmarshal(data, &mb)
+	}
+
+	keys := make([]string, 0)
+	for k, v := range mb {
+		if old, ok := ma[k]; !ok || !reflect.DeepEqual(old, v) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range ma {
+		if _, ok := mb[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	return keys
+}
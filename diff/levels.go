@@ -0,0 +1,354 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"goldtk"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// placedLevel is a level along with the world holding it.
+type placedLevel struct {
+	world goldtk.InstanceIdentifier
+	lvl   goldtk.Level
+}
+
+func levels(r goldtk.Root) ([]placedLevel, map[goldtk.InstanceIdentifier]placedLevel) {
+	order := make([]placedLevel, 0)
+	byIid := make(map[goldtk.InstanceIdentifier]placedLevel)
+	for _, w := range r.Worlds() {
+		for _, lvl := range w.Levels() {
+			p := placedLevel{world: w.Iid(), lvl: lvl}
+			order = append(order, p)
+			byIid[lvl.Iid()] = p
+		}
+	}
+
+	return order, byIid
+}
+
+// compareLevels matches the levels of a and b by instance identifier, along
+// with their fields and layers. Entities are compared by compareEntities, as
+// they may move between levels.
+func compareLevels(a, b goldtk.Root) []Change {
+	changes := make([]Change, 0)
+
+	before, beforeByIid := levels(a)
+	after, afterByIid := levels(b)
+
+	for _, pb := range after {
+		loc := Location{World: pb.world, Level: pb.lvl.Iid()}
+
+		pa, ok := beforeByIid[pb.lvl.Iid()]
+		if !ok {
+			changes = append(changes, Change{Kind: Added, Subject: Level, Location: loc, Message: string(pb.lvl.Identifier()) + " added"})
+			continue
+		}
+
+		changes = append(changes, compareLevel(loc, pa, pb)...)
+	}
+
+	for _, pa := range before {
+		if _, ok := afterByIid[pa.lvl.Iid()]; !ok {
+			loc := Location{World: pa.world, Level: pa.lvl.Iid()}
+			changes = append(changes, Change{Kind: Removed, Subject: Level, Location: loc, Message: string(pa.lvl.Identifier()) + " removed"})
+		}
+	}
+
+	return changes
+}
+
+func compareLevel(loc Location, pa, pb placedLevel) []Change {
+	changes := make([]Change, 0)
+	a, b := pa.lvl, pb.lvl
+	id := string(b.Identifier())
+
+	from := goldtk.WorldPoint{X: a.WorldX(), Y: a.WorldY()}
+	to := goldtk.WorldPoint{X: b.WorldX(), Y: b.WorldY()}
+	if pa.world != pb.world || from != to || a.WorldDepth() != b.WorldDepth() {
+		msg := fmt.Sprintf("%s moved from %d,%d to %d,%d", id, from.X, from.Y, to.X, to.Y)
+		if a.WorldDepth() != b.WorldDepth() {
+			msg += fmt.Sprintf(", depth %d to %d", a.WorldDepth(), b.WorldDepth())
+		}
+		if pa.world != pb.world {
+			msg += fmt.Sprintf(", from world %s", pa.world)
+		}
+		changes = append(changes, Change{Kind: Moved, Subject: Level, Location: loc, Message: msg, Before: from, After: to})
+	}
+
+	parts := make([]string, 0)
+	if a.Identifier() != b.Identifier() {
+		parts = append(parts, fmt.Sprintf("renamed from %s", a.Identifier()))
+	}
+	if a.PxWidth() != b.PxWidth() || a.PxHeight() != b.PxHeight() {
+		parts = append(parts, fmt.Sprintf("resized from %dx%d to %dx%d", a.PxWidth(), a.PxHeight(), b.PxWidth(), b.PxHeight()))
+	}
+	if a.BgColor().Hex() != b.BgColor().Hex() {
+		parts = append(parts, fmt.Sprintf("background changed from %s to %s", a.BgColor().Hex(), b.BgColor().Hex()))
+	}
+	if len(parts) > 0 {
+		changes = append(changes, Change{Kind: Modified, Subject: Level, Location: loc, Message: id + " " + strings.Join(parts, ", ")})
+	}
+
+	changes = append(changes, compareFields(loc, a.Fields(), b.Fields())...)
+	changes = append(changes, compareLayers(loc, a.Layers(), b.Layers())...)
+
+	return changes
+}
+
+// compareLayers matches the layers of a level by instance identifier, and
+// compares their IntGrid cells and tiles.
+func compareLayers(lvl Location, a, b []goldtk.Layer) []Change {
+	changes := make([]Change, 0)
+
+	before := make(map[goldtk.InstanceIdentifier]goldtk.Layer, len(a))
+	for _, lyr := range a {
+		before[lyr.Iid()] = lyr
+	}
+
+	after := make(map[goldtk.InstanceIdentifier]bool, len(b))
+	for _, lb := range b {
+		after[lb.Iid()] = true
+		loc := lvl
+		loc.Layer = lb.Iid()
+		id := string(lb.Identifier())
+
+		la, ok := before[lb.Iid()]
+		if !ok {
+			changes = append(changes, Change{Kind: Added, Subject: Layer, Location: loc, Message: id + " added"})
+			continue
+		}
+
+		parts := make([]string, 0)
+		if la.IsVisible() != lb.IsVisible() {
+			parts = append(parts, fmt.Sprintf("visibility changed to %t", lb.IsVisible()))
+		}
+		if !reflect.DeepEqual(la.TilesetUid(), lb.TilesetUid()) {
+			parts = append(parts, "tileset changed")
+		}
+		if len(parts) > 0 {
+			changes = append(changes, Change{Kind: Modified, Subject: Layer, Location: loc, Message: id + " " + strings.Join(parts, ", ")})
+		}
+
+		if cells := intGridCells(la.IntGrid(), lb.IntGrid()); len(cells) > 0 {
+			msg := fmt.Sprintf("%d cells of %s changed", len(cells), id)
+			changes = append(changes, Change{Kind: Modified, Subject: IntGrid, Location: loc, Message: msg, Cells: cells})
+		}
+
+		size := lb.GridSizeInPx()
+		if cells := tileCells(size, la.GridTiles(), lb.GridTiles()); len(cells) > 0 {
+			msg := fmt.Sprintf("%d tile cells of %s changed", len(cells), id)
+			changes = append(changes, Change{Kind: Modified, Subject: Tiles, Location: loc, Message: msg, Cells: cells})
+		}
+		if cells := tileCells(size, la.AutoLayerTiles(), lb.AutoLayerTiles()); len(cells) > 0 {
+			msg := fmt.Sprintf("%d auto-layer tile cells of %s changed", len(cells), id)
+			changes = append(changes, Change{Kind: Modified, Subject: Tiles, Location: loc, Message: msg, Cells: cells})
+		}
+	}
+
+	for _, la := range a {
+		if !after[la.Iid()] {
+			loc := lvl
+			loc.Layer = la.Iid()
+			changes = append(changes, Change{Kind: Removed, Subject: Layer, Location: loc, Message: string(la.Identifier()) + " removed"})
+		}
+	}
+
+	return changes
+}
+
+// intGridCells returns the cells whose value differs, over the largest of
+// both grids.
+func intGridCells(a, b goldtk.IntGrid) []goldtk.GridPoint {
+	cells := make([]goldtk.GridPoint, 0)
+	for cy := 0; cy < max(a.Height(), b.Height()); cy++ {
+		for cx := 0; cx < max(a.Width(), b.Width()); cx++ {
+			if a.At(cx, cy) != b.At(cx, cy) {
+				cells = append(cells, goldtk.GridPoint{CX: cx, CY: cy})
+			}
+		}
+	}
+
+	return cells
+}
+
+// tileKey is what makes two tiles of a cell the same.
+type tileKey struct {
+	id      int
+	flipX   bool
+	flipY   bool
+	opacity float64
+}
+
+// tileCells returns the cells whose stack of tiles differs. Tiles are placed
+// in the cell holding their top-left pixel.
+func tileCells(size int, a, b []goldtk.Tile) []goldtk.GridPoint {
+	if size <= 0 {
+		return nil
+	}
+
+	stacks := func(tiles []goldtk.Tile) map[goldtk.GridPoint][]tileKey {
+		m := make(map[goldtk.GridPoint][]tileKey)
+		for _, t := range tiles {
+			p := goldtk.GridPoint{CX: t.X() / size, CY: t.Y() / size}
+			m[p] = append(m[p], tileKey{id: t.ID(), flipX: t.FlipX(), flipY: t.FlipY(), opacity: t.Opacity()})
+		}
+		return m
+	}
+	sa, sb := stacks(a), stacks(b)
+
+	cells := make([]goldtk.GridPoint, 0)
+	for p, ka := range sa {
+		if !slices.Equal(ka, sb[p]) {
+			cells = append(cells, p)
+		}
+	}
+	for p := range sb {
+		if _, ok := sa[p]; !ok {
+			cells = append(cells, p)
+		}
+	}
+	slices.SortFunc(cells, func(p, q goldtk.GridPoint) int {
+		if p.CY != q.CY {
+			return p.CY - q.CY
+		}
+		return p.CX - q.CX
+	})
+
+	return cells
+}
+
+// placedEntity is an entity along with the level and layer holding it.
+type placedEntity struct {
+	world goldtk.InstanceIdentifier
+	level goldtk.Level
+	layer goldtk.InstanceIdentifier
+	e     goldtk.Entity
+}
+
+func (p placedEntity) location() Location {
+	return Location{World: p.world, Level: p.level.Iid(), Layer: p.layer, Entity: p.e.Iid()}
+}
+
+func entities(r goldtk.Root) ([]placedEntity, map[goldtk.InstanceIdentifier]placedEntity) {
+	order := make([]placedEntity, 0)
+	byIid := make(map[goldtk.InstanceIdentifier]placedEntity)
+	for _, w := range r.Worlds() {
+		for _, lvl := range w.Levels() {
+			for _, lyr := range lvl.Layers() {
+				for _, e := range lyr.Entities() {
+					p := placedEntity{world: w.Iid(), level: lvl, layer: lyr.Iid(), e: e}
+					order = append(order, p)
+					byIid[e.Iid()] = p
+				}
+			}
+		}
+	}
+
+	return order, byIid
+}
+
+// compareEntities matches the entities of a and b by instance identifier,
+// whatever their level and layer.
+func compareEntities(a, b goldtk.Root) []Change {
+	changes := make([]Change, 0)
+
+	before, beforeByIid := entities(a)
+	after, afterByIid := entities(b)
+
+	for _, pb := range after {
+		loc := pb.location()
+		id := string(pb.e.Identifier())
+
+		pa, ok := beforeByIid[pb.e.Iid()]
+		if !ok {
+			msg := fmt.Sprintf("%s added at %d,%d", id, pb.e.LevelPos().X, pb.e.LevelPos().Y)
+			changes = append(changes, Change{Kind: Added, Subject: Entity, Location: loc, Message: msg})
+			continue
+		}
+
+		from, to := pa.e.LevelPos(), pb.e.LevelPos()
+		if pa.level.Iid() != pb.level.Iid() || pa.layer != pb.layer || from != to {
+			msg := fmt.Sprintf("%s moved from %d,%d to %d,%d", id, from.X, from.Y, to.X, to.Y)
+			if pa.level.Iid() != pb.level.Iid() {
+				msg += fmt.Sprintf(", from level %s to %s", pa.level.Identifier(), pb.level.Identifier())
+			} else if pa.layer != pb.layer {
+				msg += fmt.Sprintf(", from layer %s", pa.layer)
+			}
+			changes = append(changes, Change{Kind: Moved, Subject: Entity, Location: loc, Message: msg, Before: from, After: to})
+		}
+
+		wa, ha := pa.e.Size()
+		wb, hb := pb.e.Size()
+		if wa != wb || ha != hb {
+			msg := fmt.Sprintf("%s resized from %dx%d to %dx%d", id, wa, ha, wb, hb)
+			changes = append(changes, Change{Kind: Modified, Subject: Entity, Location: loc, Message: msg})
+		}
+
+		changes = append(changes, compareFields(loc, pa.e.Fields(), pb.e.Fields())...)
+	}
+
+	for _, pa := range before {
+		if _, ok := afterByIid[pa.e.Iid()]; !ok {
+			changes = append(changes, Change{Kind: Removed, Subject: Entity, Location: pa.location(), Message: string(pa.e.Identifier()) + " removed"})
+		}
+	}
+
+	return changes
+}
+
+// compareFields matches fields by the unique identifier of their
+// definition, and compares their JSON values.
+func compareFields(owner Location, a, b []goldtk.Field) []Change {
+	changes := make([]Change, 0)
+
+	before := make(map[goldtk.Uid]goldtk.Field, len(a))
+	for _, f := range a {
+		before[f.DefUid()] = f
+	}
+
+	after := make(map[goldtk.Uid]bool, len(b))
+	for _, fb := range b {
+		after[fb.DefUid()] = true
+		loc := owner
+		loc.Field = fb.Identifier()
+		vb := goldtk.FieldJSON(fb)
+
+		fa, ok := before[fb.DefUid()]
+		if !ok {
+			msg := fmt.Sprintf("%s added with %s", fb.Identifier(), format(vb))
+			changes = append(changes, Change{Kind: Added, Subject: Field, Location: loc, Message: msg, After: vb})
+			continue
+		}
+
+		va := goldtk.FieldJSON(fa)
+		if reflect.DeepEqual(va, vb) {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s changed from %s to %s", fb.Identifier(), format(va), format(vb))
+		changes = append(changes, Change{Kind: Modified, Subject: Field, Location: loc, Message: msg, Before: va, After: vb})
+	}
+
+	for _, fa := range a {
+		if !after[fa.DefUid()] {
+			loc := owner
+			loc.Field = fa.Identifier()
+			changes = append(changes, Change{Kind: Removed, Subject: Field, Location: loc, Message: string(fa.Identifier()) + " removed"})
+		}
+	}
+
+	return changes
+}
+
+// format renders a field value for messages.
+func format(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
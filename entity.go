@@ -8,25 +8,55 @@ import (
 type Entity interface {
 	Identifier() Identifier
 	Iid() InstanceIdentifier
+	DefUid() Uid
+
+	// Def returns the definition of the entity, or nil when the entity does
+	// not belong to a Root.
+	Def() EntityDef
 
 	Tags() []string
 	Tile() maybe.Value[Tile]
+
+	// TileRect is the tileset rectangle used to display the entity, either
+	// from its definition or from one of its fields.
+	TileRect() maybe.Value[quicktype.TilesetRectangle]
+
 	Fields() []Field
 
 	WorldX() maybe.Value[int64]
 	WorldY() maybe.Value[int64]
 
-	// TODO something something layer offsets
+	// LocalX and LocalY are the level coordinates of the entity pivot,
+	// including the offset of its layer.
 	LocalX() int
 	LocalY() int
 
+	// Pivot returns the pivot of the entity, as fractions of its size.
+	Pivot() (x, y float64)
+
+	// LayerPos, LevelPos, WorldPos and GridPos return the position of the
+	// entity pivot in each coordinate space.
+	LayerPos() LayerPoint
+	LevelPos() LevelPoint
+	WorldPos() WorldPoint
+	GridPos() GridPoint
+
+	// LayerBounds, LevelBounds, WorldBounds and GridBounds return the area
+	// covered by the entity in each coordinate space.
+	LayerBounds() LayerRect
+	LevelBounds() LevelRect
+	WorldBounds() WorldRect
+	GridBounds() GridRect
+
 	Height() int64
 	Width() int64
 	Size() (width, height int64)
 }
 
 type entity struct {
-	inst quicktype.EntityInstance
+	inst  quicktype.EntityInstance
+	space LayerSpace
+	idx   *index
 }
 
 func (e entity) Identifier() Identifier {
@@ -37,6 +67,23 @@ func (e entity) Iid() InstanceIdentifier {
 	return InstanceIdentifier(e.inst.Iid)
 }
 
+func (e entity) DefUid() Uid {
+	return Uid(e.inst.DefUid)
+}
+
+func (e entity) Def() EntityDef {
+	if e.idx == nil {
+		return nil
+	}
+
+	def, ok := e.idx.entityDefs[e.inst.DefUid]
+	if !ok {
+		return nil
+	}
+
+	return NewEntityDef(def)
+}
+
 func (e entity) Tags() []string {
 	return e.inst.Tags
 }
@@ -46,9 +93,17 @@ func (e entity) Tile() maybe.Value[Tile] {
 	panic("implement me")
 }
 
+func (e entity) TileRect() maybe.Value[quicktype.TilesetRectangle] {
+	return maybe.From[quicktype.TilesetRectangle](e.inst.Tile)
+}
+
 func (e entity) Fields() []Field {
-	//TODO implement me
-	panic("implement me")
+	fields := make([]Field, 0)
+	for _, f := range e.inst.FieldInstances {
+		fields = append(fields, newField(f, e.idx))
+	}
+
+	return fields
 }
 
 func (e entity) WorldX() maybe.Value[int64] {
@@ -60,13 +115,58 @@ func (e entity) WorldY() maybe.Value[int64] {
 }
 
 func (e entity) LocalX() int {
-	panic("need to consider layer offset??")
-	return int(e.inst.Px[0])
+	return e.LevelPos().X
 }
 
 func (e entity) LocalY() int {
-	panic("need to consider layer offset??")
-	return int(e.inst.Px[1])
+	return e.LevelPos().Y
+}
+
+func (e entity) Pivot() (x, y float64) {
+	if len(e.inst.Pivot) < 2 {
+		return 0, 0
+	}
+
+	return e.inst.Pivot[0], e.inst.Pivot[1]
+}
+
+func (e entity) LayerPos() LayerPoint {
+	if len(e.inst.Px) < 2 {
+		return LayerPoint{}
+	}
+
+	return LayerPoint{X: int(e.inst.Px[0]), Y: int(e.inst.Px[1])}
+}
+
+func (e entity) LevelPos() LevelPoint {
+	return e.space.LayerToLevel(e.LayerPos())
+}
+
+func (e entity) WorldPos() WorldPoint {
+	return e.space.LayerToWorld(e.LayerPos())
+}
+
+func (e entity) GridPos() GridPoint {
+	return e.space.LayerToGrid(e.LayerPos())
+}
+
+func (e entity) LayerBounds() LayerRect {
+	pos := e.LayerPos()
+	pivotX, pivotY := e.Pivot()
+
+	return pivotRect(pos.X, pos.Y, int(e.inst.Width), int(e.inst.Height), pivotX, pivotY)
+}
+
+func (e entity) LevelBounds() LevelRect {
+	return e.space.LayerRectToLevel(e.LayerBounds())
+}
+
+func (e entity) WorldBounds() WorldRect {
+	return e.space.LayerRectToWorld(e.LayerBounds())
+}
+
+func (e entity) GridBounds() GridRect {
+	return e.space.LayerRectToGrid(e.LayerBounds())
 }
 
 func (e entity) Height() int64 {
@@ -81,10 +181,21 @@ func (e entity) Size() (width, height int64) {
 	return e.inst.Width, e.inst.Height
 }
 
+// NewEntity wraps an entity instance. Without its layer, the entity is assumed
+// to sit on a layer without offset, in a level at the world origin; use
+// Layer.Entities to get entities placed in their world.
 func NewEntity(inst quicktype.EntityInstance) Entity {
 	return entity{
 		inst: inst,
 	}
 }
 
+func newEntity(inst quicktype.EntityInstance, space LayerSpace, idx *index) Entity {
+	return entity{
+		inst:  inst,
+		space: space,
+		idx:   idx,
+	}
+}
+
 var _ Entity = entity{}
@@ -0,0 +1,173 @@
+package goldtk
+
+import (
+	"bytes"
+	"fmt"
+	"goldtk/quicktype"
+	"image"
+	"image/png"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Default PNG file patterns of the editor, for layer images and level images.
+const (
+	DefaultLayerPNGFilePattern = "%level_idx-%layer_name"
+	DefaultLevelPNGFilePattern = "%level_name"
+)
+
+// PNGFileVars are the values of the placeholders of a PNG file pattern.
+type PNGFileVars struct {
+	World Identifier
+	Level Identifier
+
+	// Layer is empty for level images.
+	Layer Identifier
+
+	// LevelIdx is the index of the level in its world, and LayerIdx the
+	// index of the layer in its level.
+	LevelIdx int
+	LayerIdx int
+}
+
+// ExpandPNGFilePattern replaces the placeholders of an LDtk PNG file pattern,
+// `%world`, `%level_name`, `%level_idx`, `%layer_name` and `%layer_idx`, with
+// their values. Indexes have 4 digits, as in the editor. The pattern has no
+// extension.
+func ExpandPNGFilePattern(pattern string, vars PNGFileVars) string {
+	return strings.NewReplacer(
+		"%world", string(vars.World),
+		"%level_name", string(vars.Level),
+		"%level_idx", fmt.Sprintf("%04d", vars.LevelIdx),
+		"%layer_name", string(vars.Layer),
+		"%layer_idx", fmt.Sprintf("%04d", vars.LayerIdx),
+	).Replace(pattern)
+}
+
+// WriteFS is a file system files are written to.
+type WriteFS interface {
+	// WriteFile writes the file name, a slash separated path, creating its
+	// parent directories.
+	WriteFile(name string, data []byte) error
+}
+
+type dirWriteFS string
+
+func (d dirWriteFS) WriteFile(name string, data []byte) error {
+	full := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(full, data, 0o644)
+}
+
+// DirWriteFS returns a WriteFS writing to the directory dir.
+func DirWriteFS(dir string) WriteFS {
+	return dirWriteFS(dir)
+}
+
+var _ WriteFS = dirWriteFS("")
+
+// ExportImages writes the images of every level of r to fsys, the way the
+// editor does for mode, usually r.ImageExportMode().
+//
+// Layer images are drawn over a transparent background, and level images
+// over the level background when r.ExportLevelBg() is set. Both are named
+// after r.PNGFilePattern(), or the default patterns. Entity layers and hidden
+// layers have no image, and entities are left out of level images.
+//
+// A project pattern is used for both kinds of images, so it fails when two
+// images expand to the same name, eg. a pattern without layer placeholders
+// in LayersAndLevels mode, or levels of different worlds sharing their
+// identifier with a pattern without %world.
+func ExportImages(r Root, fsys WriteFS, mode quicktype.ImageExportMode) error {
+	layerImages := mode == quicktype.OneImagePerLayer || mode == quicktype.LayersAndLevels
+	levelImages := mode == quicktype.OneImagePerLevel || mode == quicktype.LayersAndLevels
+	if !layerImages && !levelImages {
+		return nil
+	}
+
+	layerPattern, levelPattern := DefaultLayerPNGFilePattern, DefaultLevelPNGFilePattern
+	if p, ok := r.PNGFilePattern().Get(); ok && p != "" {
+		layerPattern, levelPattern = p, p
+	}
+
+	layers := NewRenderer(r, RenderOptions{IntGrid: true})
+	levels := NewRenderer(r, RenderOptions{Background: r.ExportLevelBg(), IntGrid: true})
+
+	written := make(map[string]bool)
+	write := func(name string, img image.Image) error {
+		name = path.Clean(name)
+		if written[name] {
+			return fmt.Errorf("image %s is exported twice, the PNG file pattern does not tell images apart", name)
+		}
+		written[name] = true
+
+		return WriteImage(fsys, name, img)
+	}
+
+	for _, w := range r.Worlds() {
+		for i, lvl := range w.Levels() {
+			vars := PNGFileVars{World: w.Identifier(), Level: lvl.Identifier(), LevelIdx: i}
+
+			if levelImages {
+				name := ExpandPNGFilePattern(levelPattern, vars) + ".png"
+				if err := write(name, levels.RenderLevel(lvl)); err != nil {
+					return err
+				}
+			}
+
+			if !layerImages {
+				continue
+			}
+
+			for j, lyr := range lvl.Layers() {
+				if lyr.Type() == EntityLayer || !lyr.IsVisible() {
+					continue
+				}
+
+				vars.Layer, vars.LayerIdx = lyr.Identifier(), j
+				name := ExpandPNGFilePattern(layerPattern, vars) + ".png"
+				if err := write(name, layers.RenderLayer(lvl, lyr)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteImage encodes img as PNG to the file name of fsys.
+func WriteImage(fsys WriteFS, name string, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("encoding %s: %w", name, err)
+	}
+
+	if err := fsys.WriteFile(path.Clean(name), buf.Bytes()); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// CheckLevelIdentifiers returns an error when levels of different worlds of
+// r share their identifier, as exports naming files after levels would write
+// both levels to the same files.
+func CheckLevelIdentifiers(r Root) error {
+	worlds := make(map[Identifier]Identifier)
+	for _, w := range r.Worlds() {
+		for _, lvl := range w.Levels() {
+			if other, ok := worlds[lvl.Identifier()]; ok {
+				return fmt.Errorf("level %s is in worlds %s and %s, rename one of them to export", lvl.Identifier(), other, w.Identifier())
+			}
+			worlds[lvl.Identifier()] = w.Identifier()
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"goldtk/quicktype"
 	"image/color"
 	"os"
+	"strings"
 )
 
 // Field represents an LDtk property on an entity, layer, level, or world.
@@ -12,6 +13,13 @@ type Field interface {
 	// Identifier is a unique identifier for the current field.
 	Identifier() Identifier
 
+	// DefUid is the unique ID of the field definition.
+	DefUid() Uid
+
+	// Def returns the definition of the field, or nil when the field does not
+	// belong to a Root.
+	Def() FieldDef
+
 	// FieldValue is an interface for interact with the underlying value of the field.
 	Value() FieldValue
 
@@ -22,12 +30,30 @@ type Field interface {
 type field struct {
 	inst quicktype.FieldInstance
 	val  FieldValue
+	idx  *index
 }
 
 func (f field) Identifier() Identifier {
 	return Identifier(f.inst.Identifier)
 }
 
+func (f field) DefUid() Uid {
+	return Uid(f.inst.DefUid)
+}
+
+func (f field) Def() FieldDef {
+	if f.idx == nil {
+		return nil
+	}
+
+	def, ok := f.idx.fieldDefs[f.inst.DefUid]
+	if !ok {
+		return nil
+	}
+
+	return NewFieldDef(def)
+}
+
 func (f field) Value() FieldValue {
 	return f.val
 }
@@ -37,13 +63,20 @@ func (f field) Type() string {
 }
 
 func NewField(inst quicktype.FieldInstance) Field {
-	return field{inst, NewFieldValue(inst.Value)}
+	return newField(inst, nil)
+}
+
+func newField(inst quicktype.FieldInstance, idx *index) Field {
+	return field{inst, decodeFieldValue(inst.Type, inst.Value, idx), idx}
 }
 
 var _ Field = field{}
 
 // FieldValue is an interface to represent the underlying value of a field.
 type FieldValue interface {
+	// IsNull returns true when the field has no value.
+	IsNull() bool
+
 	Int() int
 	Int32() int32
 	Int64() int64
@@ -75,6 +108,11 @@ type Multilines struct {
 	classification string
 }
 
+// String returns the text of the field.
+func (m Multilines) String() string {
+	return m.contents
+}
+
 type File struct {
 	Path string
 }
@@ -89,6 +127,10 @@ type value struct {
 	data interface{}
 }
 
+func (v value) IsNull() bool {
+	return v.data == nil
+}
+
 func (v value) Int() int {
 	var zeroValue int
 
@@ -102,8 +144,8 @@ func (v value) Int() int {
 func (v value) Int32() int32 {
 	var zeroValue int32
 
-	if data, ok := v.data.(int32); ok {
-		return data
+	if data, ok := v.data.(int); ok {
+		return int32(data)
 	}
 
 	return zeroValue
@@ -112,18 +154,23 @@ func (v value) Int32() int32 {
 func (v value) Int64() int64 {
 	var zeroValue int64
 
-	if data, ok := v.data.(int64); ok {
-		return data
+	if data, ok := v.data.(int); ok {
+		return int64(data)
 	}
 
 	return zeroValue
 }
 
+// Float64 also converts Int values, whose JSON form can be a float without
+// a fraction.
 func (v value) Float64() float64 {
 	var zeroValue float64
 
-	if data, ok := v.data.(float64); ok {
+	switch data := v.data.(type) {
+	case float64:
 		return data
+	case int:
+		return float64(data)
 	}
 
 	return zeroValue
@@ -243,6 +290,136 @@ func NewFieldValue(v any) FieldValue {
 
 var _ FieldValue = value{}
 
+// decodeFieldValue converts the raw JSON value of a field to the Go type
+// returned by the matching FieldValue accessor.
+func decodeFieldValue(typ string, raw any, idx *index) FieldValue {
+	if inner, ok := strings.CutPrefix(typ, "Array<"); ok {
+		items, ok := raw.([]any)
+		if !ok {
+			return NewFieldValue(raw)
+		}
+
+		inner = strings.TrimSuffix(inner, ">")
+		values := make([]FieldValue, len(items))
+		for i, item := range items {
+			values[i] = decodeFieldValue(inner, item, idx)
+		}
+
+		return NewFieldValue(values)
+	}
+
+	if raw == nil {
+		return NewFieldValue(nil)
+	}
+
+	switch typ {
+	case "Int":
+		if n, ok := raw.(float64); ok {
+			return NewFieldValue(int(n))
+		}
+	case "Multilines":
+		if s, ok := raw.(string); ok {
+			return NewFieldValue(Multilines{contents: s})
+		}
+	case "Color":
+		if s, ok := raw.(string); ok {
+			return NewFieldValue(color.Color(ColorFromHex(s).RGBA()))
+		}
+	case "FilePath":
+		if s, ok := raw.(string); ok {
+			return NewFieldValue(File{Path: s})
+		}
+	case "Point":
+		if m, ok := raw.(map[string]any); ok {
+			cx, _ := m["cx"].(float64)
+			cy, _ := m["cy"].(float64)
+			return NewFieldValue(Point{X: int(cx), Y: int(cy)})
+		}
+	case "EntityRef":
+		if ref, ok := decodeReference(raw); ok {
+			return NewFieldValue(reference{inst: ref, idx: idx})
+		}
+	}
+
+	// Float, Bool, String and enum values already have their Go type.
+	return NewFieldValue(raw)
+}
+
+// FieldJSON returns the value of the field in its JSON form, as the editor
+// writes it in `__value`: colors as hex strings, points as `cx`/`cy` objects,
+// entity references and tiles as objects, and null for missing values.
+func FieldJSON(f Field) any {
+	return fieldValueJSON(f.Type(), f.Value())
+}
+
+func fieldValueJSON(typ string, v FieldValue) any {
+	if v == nil || v.IsNull() {
+		return nil
+	}
+
+	if inner, ok := strings.CutPrefix(typ, "Array<"); ok {
+		inner = strings.TrimSuffix(inner, ">")
+		items := make([]any, 0)
+		for _, item := range v.Array() {
+			items = append(items, fieldValueJSON(inner, item))
+		}
+		return items
+	}
+
+	switch typ {
+	case "Int":
+		return v.Int()
+	case "Float":
+		return v.Float64()
+	case "Bool":
+		return v.Bool()
+	case "Color":
+		return ColorFromColor(v.Color()).Hex()
+	case "Multilines":
+		return v.Multilines().String()
+	case "FilePath":
+		return v.FilePath()
+	case "Point":
+		p := v.Point()
+		return map[string]int{"cx": p.X, "cy": p.Y}
+	case "EntityRef":
+		if ref := v.EntityRef(); ref != nil {
+			return ref.Reference()
+		}
+		return nil
+	case "Tile":
+		// Tile values keep the tileset rectangle they were decoded from.
+		if raw, ok := v.(value); ok {
+			if rect, ok := raw.data.(map[string]any); ok {
+				return rect
+			}
+		}
+		return nil
+	}
+
+	// String and enum values.
+	return v.String()
+}
+
+func decodeReference(raw any) (quicktype.ReferenceToAnEntityInstance, bool) {
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return quicktype.ReferenceToAnEntityInstance{}, false
+	}
+
+	str := func(key string) string {
+		s, _ := m[key].(string)
+		return s
+	}
+
+	return quicktype.ReferenceToAnEntityInstance{
+		EntityIid: str("entityIid"),
+		LayerIid:  str("layerIid"),
+		LevelIid:  str("levelIid"),
+		WorldIid:  str("worldIid"),
+	}, true
+}
+
 func colorToHex(c color.Color) string {
 	rgba := color.RGBAModel.Convert(c).(color.RGBA)
 	return fmt.Sprintf("#%02x%02x%02x%02x", rgba.R, rgba.G, rgba.B, rgba.A)
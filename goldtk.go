@@ -1,9 +1,5 @@
 package goldtk
 
-import (
-	"goldtk/quicktype"
-)
-
 //type Serializer[T any] interface {
 //	To() T
 //	From(T) error
@@ -13,23 +9,6 @@ type Identifier string
 type InstanceIdentifier string
 type Uid int
 
-type World interface {
-}
-
-type Reference interface {
-	Reference() quicktype.ReferenceToAnEntityInstance
-
-	EntityIid() string
-	LayerIid() string
-	LevelIid() string
-	WorldIid() string
-
-	Entity() interface{} // TODO: Entity here
-	Layer() interface{}  // TODO: Entity here
-	Level() interface{}  // TODO: Entity here
-	World() interface{}  // TODO: Entity here
-}
-
 //type Tile interface {
 //	LayerDefUid() int64
 //	TilesetRectangle() quicktype.TilesetRectangle
@@ -89,5 +68,3 @@ type Reference interface {
 //}
 //
 //var _ Tile = tile{}
-
-type IntGrid interface{}
@@ -0,0 +1,329 @@
+package goldtk
+
+import (
+	"fmt"
+	"goldtk/quicktype"
+	"slices"
+)
+
+// ReferenceGraph is the directed graph of every entity reference field in a
+// project. Each entity is a node, and each EntityRef value is an edge from the
+// entity holding the field to the referenced entity.
+type ReferenceGraph interface {
+	// Edges returns every reference, in project order.
+	Edges() []ReferenceEdge
+
+	// From returns the references held by the fields of the entity.
+	From(iid InstanceIdentifier) []ReferenceEdge
+
+	// To returns the references pointing to the entity.
+	To(iid InstanceIdentifier) []ReferenceEdge
+
+	// Validate checks every reference against the field definition holding
+	// it, and returns the problems found.
+	Validate() []ReferenceProblem
+}
+
+// ReferenceEdge is a single entity reference, held by a field of an entity.
+type ReferenceEdge struct {
+	Source      InstanceIdentifier
+	SourceLevel InstanceIdentifier
+
+	Field       Identifier
+	FieldDefUid Uid
+
+	Target Reference
+}
+
+// ReferenceProblemKind classifies the problems reported by a ReferenceGraph.
+type ReferenceProblemKind string
+
+const (
+	// DanglingReference is a reference to an entity missing from the project.
+	DanglingReference ReferenceProblemKind = "DanglingReference"
+
+	// DisallowedReference is a reference to an entity the field definition
+	// does not accept, per its allowed refs and out-of-level settings.
+	DisallowedReference ReferenceProblemKind = "DisallowedReference"
+
+	// AsymmetricReference is a reference of a symmetrical field which the
+	// target does not reference back.
+	AsymmetricReference ReferenceProblemKind = "AsymmetricReference"
+
+	// ReferenceCycle is a loop of references between entities.
+	ReferenceCycle ReferenceProblemKind = "ReferenceCycle"
+)
+
+// ReferenceProblem describes an invalid reference, or a reference cycle.
+type ReferenceProblem struct {
+	Kind ReferenceProblemKind
+
+	// Edge is the offending reference. It is empty for cycles.
+	Edge ReferenceEdge
+
+	// Cycle lists the entities of a cycle, in reference order.
+	Cycle []InstanceIdentifier
+
+	Message string
+}
+
+type referenceGraph struct {
+	root  Root
+	nodes map[InstanceIdentifier]graphNode
+	order []InstanceIdentifier
+	edges []ReferenceEdge
+	from  map[InstanceIdentifier][]int
+	to    map[InstanceIdentifier][]int
+}
+
+type graphNode struct {
+	identifier Identifier
+	defUid     Uid
+	tags       []string
+	level      InstanceIdentifier
+}
+
+func (g referenceGraph) Edges() []ReferenceEdge {
+	return g.edges
+}
+
+func (g referenceGraph) From(iid InstanceIdentifier) []ReferenceEdge {
+	return g.pick(g.from[iid])
+}
+
+func (g referenceGraph) To(iid InstanceIdentifier) []ReferenceEdge {
+	return g.pick(g.to[iid])
+}
+
+func (g referenceGraph) pick(indexes []int) []ReferenceEdge {
+	edges := make([]ReferenceEdge, len(indexes))
+	for i, idx := range indexes {
+		edges[i] = g.edges[idx]
+	}
+
+	return edges
+}
+
+func (g referenceGraph) Validate() []ReferenceProblem {
+	problems := make([]ReferenceProblem, 0)
+
+	for _, edge := range g.edges {
+		target, ok := g.nodes[edge.Target.EntityIid()]
+		if !ok {
+			problems = append(problems, ReferenceProblem{
+				Kind:    DanglingReference,
+				Edge:    edge,
+				Message: fmt.Sprintf("field %s of entity %s references missing entity %s", edge.Field, edge.Source, edge.Target.EntityIid()),
+			})
+			continue
+		}
+
+		def, ok := g.root.FieldDefByUid(edge.FieldDefUid)
+		if !ok {
+			continue
+		}
+
+		if reason, ok := g.allowed(edge, def, target); !ok {
+			problems = append(problems, ReferenceProblem{
+				Kind:    DisallowedReference,
+				Edge:    edge,
+				Message: fmt.Sprintf("field %s of entity %s references %s %s: %s", edge.Field, edge.Source, target.identifier, edge.Target.EntityIid(), reason),
+			})
+		}
+
+		if def.SymmetricalRef && !g.referencesBack(edge) {
+			problems = append(problems, ReferenceProblem{
+				Kind:    AsymmetricReference,
+				Edge:    edge,
+				Message: fmt.Sprintf("field %s of entity %s references %s, which does not reference it back", edge.Field, edge.Source, edge.Target.EntityIid()),
+			})
+		}
+	}
+
+	for _, cycle := range g.cycles() {
+		problems = append(problems, ReferenceProblem{
+			Kind:    ReferenceCycle,
+			Cycle:   cycle,
+			Message: fmt.Sprintf("reference cycle between %d entities starting at %s", len(cycle), cycle[0]),
+		})
+	}
+
+	return problems
+}
+
+// allowed checks the target of a reference against its field definition, and
+// returns the reason it is refused.
+func (g referenceGraph) allowed(edge ReferenceEdge, def quicktype.FieldDefinition, target graphNode) (string, bool) {
+	if !def.AllowOutOfLevelRef && target.level != edge.SourceLevel {
+		return "references outside of the level are not allowed", false
+	}
+
+	source := g.nodes[edge.Source]
+
+	switch def.AllowedRefs {
+	case quicktype.OnlySame:
+		if target.defUid != source.defUid {
+			return fmt.Sprintf("only %s entities are allowed", source.identifier), false
+		}
+	case quicktype.OnlySpecificEntity:
+		if def.AllowedRefsEntityUid != nil && int64(target.defUid) != *def.AllowedRefsEntityUid {
+			return fmt.Sprintf("only entities of definition %d are allowed", *def.AllowedRefsEntityUid), false
+		}
+	case quicktype.OnlyTags:
+		if !slices.ContainsFunc(def.AllowedRefTags, func(tag string) bool {
+			return slices.Contains(target.tags, tag)
+		}) {
+			return fmt.Sprintf("only entities tagged %v are allowed", def.AllowedRefTags), false
+		}
+	}
+
+	return "", true
+}
+
+// referencesBack returns true if the target of the edge references its source
+// through the same field definition.
+func (g referenceGraph) referencesBack(edge ReferenceEdge) bool {
+	for _, back := range g.From(edge.Target.EntityIid()) {
+		if back.FieldDefUid == edge.FieldDefUid && back.Target.EntityIid() == edge.Source {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cycles returns the strongly connected components of the graph which contain
+// a loop, using Tarjan's algorithm. References of symmetrical fields always
+// come in pairs, so they are left out.
+func (g referenceGraph) cycles() [][]InstanceIdentifier {
+	next := make(map[InstanceIdentifier][]InstanceIdentifier)
+	for _, edge := range g.edges {
+		if def, ok := g.root.FieldDefByUid(edge.FieldDefUid); ok && def.SymmetricalRef {
+			continue
+		}
+		if _, ok := g.nodes[edge.Target.EntityIid()]; !ok {
+			continue
+		}
+
+		next[edge.Source] = append(next[edge.Source], edge.Target.EntityIid())
+	}
+
+	var (
+		counter  int
+		indexes  = make(map[InstanceIdentifier]int)
+		lowlinks = make(map[InstanceIdentifier]int)
+		onStack  = make(map[InstanceIdentifier]bool)
+		stack    []InstanceIdentifier
+		cycles   [][]InstanceIdentifier
+		visit    func(iid InstanceIdentifier)
+	)
+
+	visit = func(iid InstanceIdentifier) {
+		indexes[iid] = counter
+		lowlinks[iid] = counter
+		counter++
+		stack = append(stack, iid)
+		onStack[iid] = true
+
+		for _, target := range next[iid] {
+			if _, seen := indexes[target]; !seen {
+				visit(target)
+				lowlinks[iid] = min(lowlinks[iid], lowlinks[target])
+			} else if onStack[target] {
+				lowlinks[iid] = min(lowlinks[iid], indexes[target])
+			}
+		}
+
+		if lowlinks[iid] != indexes[iid] {
+			return
+		}
+
+		var component []InstanceIdentifier
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+
+			if top == iid {
+				break
+			}
+		}
+
+		if len(component) > 1 || slices.Contains(next[iid], iid) {
+			slices.Reverse(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, iid := range g.order {
+		if _, seen := indexes[iid]; !seen {
+			visit(iid)
+		}
+	}
+
+	return cycles
+}
+
+// NewReferenceGraph walks every entity of the project, loading external
+// levels, and collects the references held by their fields.
+func NewReferenceGraph(r Root) ReferenceGraph {
+	g := referenceGraph{
+		root:  r,
+		nodes: make(map[InstanceIdentifier]graphNode),
+		from:  make(map[InstanceIdentifier][]int),
+		to:    make(map[InstanceIdentifier][]int),
+	}
+
+	for _, lvl := range r.Levels() {
+		for _, lyr := range lvl.Layers() {
+			for _, e := range lyr.Entities() {
+				g.nodes[e.Iid()] = graphNode{
+					identifier: e.Identifier(),
+					defUid:     e.DefUid(),
+					tags:       e.Tags(),
+					level:      lvl.Iid(),
+				}
+				g.order = append(g.order, e.Iid())
+
+				for _, f := range e.Fields() {
+					for _, ref := range fieldReferences(f.Value()) {
+						g.add(ReferenceEdge{
+							Source:      e.Iid(),
+							SourceLevel: lvl.Iid(),
+							Field:       f.Identifier(),
+							FieldDefUid: f.DefUid(),
+							Target:      ref,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return g
+}
+
+func (g *referenceGraph) add(edge ReferenceEdge) {
+	g.edges = append(g.edges, edge)
+	g.from[edge.Source] = append(g.from[edge.Source], len(g.edges)-1)
+	g.to[edge.Target.EntityIid()] = append(g.to[edge.Target.EntityIid()], len(g.edges)-1)
+}
+
+// fieldReferences returns the references of a single or array EntityRef value.
+func fieldReferences(v FieldValue) []Reference {
+	if ref := v.EntityRef(); ref != nil {
+		return []Reference{ref}
+	}
+
+	refs := make([]Reference, 0)
+	for _, item := range v.Array() {
+		if ref := item.EntityRef(); ref != nil {
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
+var _ ReferenceGraph = referenceGraph{}
@@ -0,0 +1,163 @@
+package goldtk
+
+import (
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// imageCacheBudget is the memory, in bytes, of the decoded images kept by
+// the image cache before the least recently used ones are dropped.
+const imageCacheBudget = 256 << 20
+
+// dirFS is a file system of a directory whose resolved path is known, so
+// that its images are shared between projects.
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+// DirFS returns a file system for the files of the directory dir, as
+// os.DirFS does. Tileset images read through it are cached by resolved path,
+// and shared by every project of the process loaded from the same files.
+// Images of other file systems are decoded by each project.
+func DirFS(dir string) fs.FS {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	return dirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+// imageEntry is a decoded image, along with the modification info of the
+// file it was decoded from.
+type imageEntry struct {
+	size    int64
+	modTime time.Time
+
+	// ready is closed once img and err are set.
+	ready chan struct{}
+	img   image.Image
+	err   error
+
+	// bytes is an estimate of the memory used by img, set once it is
+	// decoded, and used the tick of the last use of the entry.
+	bytes int64
+	used  uint64
+}
+
+// imageCache shares decoded images between every project of the process, by
+// resolved file path.
+type imageCache struct {
+	mu      sync.Mutex
+	entries map[string]*imageEntry
+	tick    uint64
+}
+
+var images = &imageCache{entries: make(map[string]*imageEntry)}
+
+// decode returns the image file name of sys, decoding it only when it is
+// not cached or changed since. Only images of DirFS file systems are cached.
+func (c *imageCache) decode(sys fs.FS, name string) (image.Image, error) {
+	dir, ok := sys.(dirFS)
+	if !ok {
+		return decodeImage(sys, name)
+	}
+
+	info, err := fs.Stat(sys, name)
+	if err != nil {
+		return decodeImage(sys, name)
+	}
+
+	key := filepath.Join(dir.dir, filepath.FromSlash(name))
+
+	c.mu.Lock()
+	c.tick++
+	entry, ok := c.entries[key]
+	if !ok || entry.size != info.Size() || !entry.modTime.Equal(info.ModTime()) {
+		// A changed file replaces its previous image, so that the cache
+		// holds a single image per file.
+		entry = &imageEntry{size: info.Size(), modTime: info.ModTime(), ready: make(chan struct{}), used: c.tick}
+		c.entries[key] = entry
+		c.mu.Unlock()
+
+		entry.img, entry.err = decodeImage(sys, name)
+		close(entry.ready)
+
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			if entry.err != nil {
+				// Failures are not cached, so that a fixed file is read
+				// again.
+				delete(c.entries, key)
+			} else {
+				b := entry.img.Bounds()
+				entry.bytes = int64(b.Dx()) * int64(b.Dy()) * 4
+			}
+		}
+		c.evict()
+		c.mu.Unlock()
+
+		return entry.img, entry.err
+	}
+	entry.used = c.tick
+	c.mu.Unlock()
+
+	<-entry.ready
+	return entry.img, entry.err
+}
+
+// evict drops the least recently used images while the cache is over
+// budget. Images being decoded are kept. The lock must be held.
+func (c *imageCache) evict() {
+	var total int64
+	for _, entry := range c.entries {
+		total += entry.bytes
+	}
+
+	for total > imageCacheBudget {
+		oldest := ""
+		for key, entry := range c.entries {
+			if entry.bytes == 0 {
+				continue
+			}
+			if oldest == "" || entry.used < c.entries[oldest].used {
+				oldest = key
+			}
+		}
+		if oldest == "" {
+			return
+		}
+
+		total -= c.entries[oldest].bytes
+		delete(c.entries, oldest)
+	}
+}
+
+// ClearImageCache drops every tileset image decoded by the process. Images
+// still used by a project stay in memory until the project is released.
+func ClearImageCache() {
+	images.mu.Lock()
+	defer images.mu.Unlock()
+
+	images.entries = make(map[string]*imageEntry)
+}
+
+func decodeImage(sys fs.FS, name string) (image.Image, error) {
+	f, err := sys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("opening tileset %s: %w", name, err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding tileset image %s: %w", name, err)
+	}
+
+	return img, nil
+}
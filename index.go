@@ -0,0 +1,502 @@
+package goldtk
+
+import (
+	"encoding/json"
+	"fmt"
+	"goldtk/quicktype"
+	"io/fs"
+	"path"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// index locates every world, level, layer and entity of a project by its
+// instance identifier, and every definition by its unique ID. It owns a copy of
+// the project levels, so that external levels can be loaded into it on demand
+// without touching the caller's data.
+type index struct {
+	sys fs.FS
+	dir string
+
+	mu       sync.Mutex
+	worlds   map[string]World
+	levels   map[string]*levelEntry
+	layers   map[string]*layerEntry
+	entities map[string]*entityEntry
+
+	// owners maps the iid of layers and entities of external levels to the
+	// iid of their level: those listed in the table of contents, and those of
+	// every level loaded so far. A miss on one of them loads a single level.
+	owners map[string]string
+
+	// Definitions never change once indexed, and are read without the lock.
+	entityDefs  map[int64]quicktype.EntityDefinition
+	layerDefs   map[int64]quicktype.LayerDefinition
+	enumDefs    map[int64]quicktype.EnumDefinition
+	tilesetDefs map[int64]quicktype.TilesetDefinition
+	fieldDefs   map[int64]quicktype.FieldDefinition
+
+	// tilesets holds the tilesets by UID. The map never changes once
+	// filled, and is read without the lock.
+	tilesets map[int64]*tilesetEntry
+}
+
+// tilesetEntry is a tileset, whose image is decoded on first use by projects
+// loaded with LoadLazy.
+type tilesetEntry struct {
+	path string
+	def  quicktype.TilesetDefinition
+
+	mu sync.Mutex
+	ts Tileset
+}
+
+type levelEntry struct {
+	worldIid string
+	inst     *quicktype.Level
+	loaded   bool
+
+	// loading is closed once the level file being read is loaded, or failed
+	// to load.
+	loading chan struct{}
+
+	// migrations lists the changes made to the external level file when
+	// loaded, see Migrate.
+	migrations []Migration
+}
+
+type layerEntry struct {
+	level *levelEntry
+	inst  *quicktype.LayerInstance
+}
+
+type entityEntry struct {
+	layer *layerEntry
+	inst  *quicktype.EntityInstance
+}
+
+// newIndex creates an empty index. Paths found in the project are resolved
+// from dir, the directory of the project file in sys.
+func newIndex(sys fs.FS, dir string) *index {
+	return &index{
+		sys:      sys,
+		dir:      dir,
+		worlds:   make(map[string]World),
+		levels:   make(map[string]*levelEntry),
+		layers:   make(map[string]*layerEntry),
+		entities: make(map[string]*entityEntry),
+		owners:   make(map[string]string),
+
+		entityDefs:  make(map[int64]quicktype.EntityDefinition),
+		layerDefs:   make(map[int64]quicktype.LayerDefinition),
+		enumDefs:    make(map[int64]quicktype.EnumDefinition),
+		tilesetDefs: make(map[int64]quicktype.TilesetDefinition),
+		fieldDefs:   make(map[int64]quicktype.FieldDefinition),
+
+		tilesets: make(map[int64]*tilesetEntry),
+	}
+}
+
+// addDefs indexes every definition of the project by UID.
+func (idx *index) addDefs(defs quicktype.Definitions) {
+	for _, def := range defs.Entities {
+		idx.entityDefs[def.Uid] = def
+		for _, field := range def.FieldDefs {
+			idx.fieldDefs[field.Uid] = field
+		}
+	}
+	for _, def := range defs.Layers {
+		idx.layerDefs[def.Uid] = def
+	}
+	for _, def := range defs.Enums {
+		idx.enumDefs[def.Uid] = def
+	}
+	for _, def := range defs.ExternalEnums {
+		idx.enumDefs[def.Uid] = def
+	}
+	for _, def := range defs.Tilesets {
+		idx.tilesetDefs[def.Uid] = def
+	}
+	for _, def := range defs.LevelFields {
+		idx.fieldDefs[def.Uid] = def
+	}
+}
+
+// addTilesets indexes the tilesets by UID, without decoding their images.
+func (idx *index) addTilesets(defs []quicktype.TilesetDefinition) {
+	for _, def := range defs {
+		// Embedded atlases, such as the LDtk icons, ship with the editor.
+		if def.EmbedAtlas != nil {
+			continue
+		}
+
+		entry := &tilesetEntry{def: def}
+		if def.RelPath != nil {
+			entry.path = idx.resolve(*def.RelPath)
+		}
+		idx.tilesets[def.Uid] = entry
+	}
+}
+
+// tileset returns the tileset with the given UID, decoding its image when
+// not done yet. Failures are not remembered, so that an image caught while
+// being saved decodes on the next attempt.
+func (idx *index) tileset(uid int64) (Tileset, error) {
+	entry, ok := idx.tilesets[uid]
+	if !ok {
+		return nil, fmt.Errorf("tileset %d not found", uid)
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.ts != nil {
+		return entry.ts, nil
+	}
+
+	if entry.def.RelPath == nil {
+		return nil, fmt.Errorf("error creating tileset: tileset definition requires relative path")
+	}
+
+	ts, err := openTileset(entry.def, idx.sys, entry.path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating tileset: %v", err)
+	}
+	entry.ts = ts
+
+	return ts, nil
+}
+
+// openTilesets indexes the tilesets, and decodes their images in parallel.
+func (idx *index) openTilesets(defs []quicktype.TilesetDefinition) ([]Tileset, error) {
+	idx.addTilesets(defs)
+	defs = slices.DeleteFunc(slices.Clone(defs), func(def quicktype.TilesetDefinition) bool {
+		return def.EmbedAtlas != nil
+	})
+
+	tilesets := make([]Tileset, len(defs))
+	errs := make([]error, len(defs))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.GOMAXPROCS(0), len(defs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				tilesets[i], errs[i] = idx.tileset(defs[i].Uid)
+			}
+		}()
+	}
+
+	for i := range defs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := range defs {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+	}
+
+	return tilesets, nil
+}
+
+// addWorld indexes the levels of the world, and returns a world whose levels
+// share the index.
+func (idx *index) addWorld(inst quicktype.World) World {
+	inst.Levels = slices.Clone(inst.Levels)
+
+	for i := range inst.Levels {
+		entry := &levelEntry{
+			worldIid: inst.Iid,
+			inst:     &inst.Levels[i],
+			loaded:   inst.Levels[i].ExternalRelPath == nil || inst.Levels[i].LayerInstances != nil,
+		}
+
+		idx.levels[entry.inst.Iid] = entry
+		if entry.loaded {
+			idx.addLayers(entry)
+		}
+	}
+
+	w := newWorld(inst, idx)
+	idx.worlds[inst.Iid] = w
+
+	return w
+}
+
+func (idx *index) addLayers(lvl *levelEntry) {
+	for i := range lvl.inst.LayerInstances {
+		lyr := &layerEntry{
+			level: lvl,
+			inst:  &lvl.inst.LayerInstances[i],
+		}
+		idx.layers[lyr.inst.Iid] = lyr
+
+		for j := range lyr.inst.EntityInstances {
+			idx.entities[lyr.inst.EntityInstances[j].Iid] = &entityEntry{
+				layer: lyr,
+				inst:  &lyr.inst.EntityInstances[j],
+			}
+		}
+
+		if lvl.inst.ExternalRelPath != nil {
+			idx.owners[lyr.inst.Iid] = lvl.inst.Iid
+			for _, e := range lyr.inst.EntityInstances {
+				idx.owners[e.Iid] = lvl.inst.Iid
+			}
+		}
+	}
+}
+
+// addTOC records the level of the entities listed in the table of contents,
+// so that they are found without reading every external level.
+func (idx *index) addTOC(toc []quicktype.LdtkTableOfContentEntry) {
+	add := func(ref quicktype.ReferenceToAnEntityInstance) {
+		idx.owners[ref.EntityIid] = ref.LevelIid
+		idx.owners[ref.LayerIid] = ref.LevelIid
+	}
+
+	for _, entry := range toc {
+		for _, ref := range entry.Instances {
+			add(ref)
+		}
+		for _, data := range entry.InstancesData {
+			add(data.Iids)
+		}
+	}
+}
+
+// level returns the level with the given iid, loading it first when it is
+// stored in an external file.
+func (idx *index) level(iid string) (Level, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	entry, err := idx.levelEntry(iid)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.wrap(idx), nil
+}
+
+// levelInst returns the loaded instance of the level with the given iid.
+func (idx *index) levelInst(iid string) (quicktype.Level, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	entry, err := idx.levelEntry(iid)
+	if err != nil {
+		return quicktype.Level{}, err
+	}
+
+	return *entry.inst, nil
+}
+
+// levelEntry finds and loads a level. The lock must be held, and is released
+// while the file of an external level is read.
+func (idx *index) levelEntry(iid string) (*levelEntry, error) {
+	entry, ok := idx.levels[iid]
+	if !ok {
+		return nil, fmt.Errorf("level %s not found", iid)
+	}
+
+	for !entry.loaded {
+		if entry.loading != nil {
+			// Wait for the load in progress, and try again when it failed.
+			done := entry.loading
+			idx.mu.Unlock()
+			<-done
+			idx.mu.Lock()
+			continue
+		}
+
+		if err := idx.load(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
+
+// load reads the layers of an external level, releasing the lock while the
+// file is read. Failures are not remembered, so that a level caught while
+// being saved loads on the next attempt. The lock must be held.
+func (idx *index) load(entry *levelEntry) error {
+	done := make(chan struct{})
+	entry.loading = done
+	rel := *entry.inst.ExternalRelPath
+
+	idx.mu.Unlock()
+	ext, err := idx.readLevel(rel)
+	idx.mu.Lock()
+
+	entry.loading = nil
+	close(done)
+	if err != nil {
+		return err
+	}
+
+	ext.ExternalRelPath = entry.inst.ExternalRelPath
+	if ext.Iid == "" {
+		// Keep the instance identifier given by Migrate to the level, from
+		// which its layers derive theirs.
+		ext.Iid = entry.inst.Iid
+	}
+	entry.migrations = migrateLevel(&ext, entry.worldIid, 0)
+	*entry.inst = ext
+	entry.loaded = true
+	idx.addLayers(entry)
+
+	return nil
+}
+
+// unload drops the layers of an external level, which are read again on the
+// next access, and returns the level as loaded until then. Levels stored in
+// the project file stay loaded.
+func (idx *index) unload(iid string) (Level, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	entry, ok := idx.levels[iid]
+	if !ok || !entry.loaded || entry.inst.ExternalRelPath == nil {
+		return nil, false
+	}
+	lvl := entry.wrap(idx)
+
+	for _, lyr := range entry.inst.LayerInstances {
+		delete(idx.layers, lyr.Iid)
+		for _, e := range lyr.EntityInstances {
+			delete(idx.entities, e.Iid)
+		}
+	}
+
+	entry.inst.LayerInstances = nil
+	entry.loaded = false
+
+	return lvl, true
+}
+
+// readLevel decodes the external level file rel.
+func (idx *index) readLevel(rel string) (quicktype.Level, error) {
+	path := idx.resolve(rel)
+	data, err := fs.ReadFile(idx.sys, path)
+	if err != nil {
+		return quicktype.Level{}, fmt.Errorf("reading level %s: %w", path, err)
+	}
+
+	var ext quicktype.Level
+	if err := json.Unmarshal(data, &ext); err != nil {
+		return quicktype.Level{}, fmt.Errorf("decoding level %s: %w", path, err)
+	}
+
+	return ext, nil
+}
+
+// resolve converts a path relative to the project file to a path of the
+// index file system.
+func (idx *index) resolve(rel string) string {
+	return path.Join(idx.dir, rel)
+}
+
+// locate loads the level holding the layer or entity iid, when known from the
+// level hint or the owners of external instances. Instances of levels never
+// loaded, and not in the table of contents, are not found. The lock must be
+// held.
+func (idx *index) locate(iid, levelIid string) {
+	if levelIid == "" {
+		levelIid = idx.owners[iid]
+	}
+	if levelIid != "" {
+		// A level which fails to load leaves the instance missing.
+		_, _ = idx.levelEntry(levelIid)
+	}
+}
+
+// layer returns the layer with the given iid. The iid of the level holding
+// it, when known, is loaded first.
+func (idx *index) layer(iid, levelIid string) (Layer, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	entry, ok := idx.layers[iid]
+	if !ok {
+		idx.locate(iid, levelIid)
+		entry, ok = idx.layers[iid]
+	}
+	if !ok {
+		return nil, false
+	}
+
+	return entry.wrap(idx), true
+}
+
+// entity returns the entity with the given iid. The iid of the level holding
+// it, when known, is loaded first.
+func (idx *index) entity(iid, levelIid string) (Entity, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	entry, ok := idx.entities[iid]
+	if !ok {
+		idx.locate(iid, levelIid)
+		entry, ok = idx.entities[iid]
+	}
+	if !ok {
+		return nil, false
+	}
+
+	return entry.wrap(idx), true
+}
+
+// lookup returns the world, level, layer or entity with the given iid.
+func (idx *index) lookup(iid string) (any, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if entry, ok := idx.levels[iid]; ok {
+		return entry.wrap(idx), true
+	}
+	if w, ok := idx.worlds[iid]; ok {
+		return w, true
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if entry, ok := idx.entities[iid]; ok {
+			return entry.wrap(idx), true
+		}
+		if entry, ok := idx.layers[iid]; ok {
+			return entry.wrap(idx), true
+		}
+
+		// The iid may belong to a layer or entity of an external level.
+		idx.locate(iid, "")
+	}
+
+	return nil, false
+}
+
+func (idx *index) world(iid string) (World, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	w, ok := idx.worlds[iid]
+	return w, ok
+}
+
+func (e *levelEntry) wrap(idx *index) Level {
+	return newLevel(*e.inst, idx)
+}
+
+func (e *layerEntry) wrap(idx *index) Layer {
+	return newLevelLayer(*e.inst, *e.level.inst, idx)
+}
+
+func (e *entityEntry) wrap(idx *index) Entity {
+	return newEntity(*e.inst, e.layer.wrap(idx).Space(), idx)
+}
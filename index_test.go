@@ -0,0 +1,175 @@
+package goldtk
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// readCounter is a file system which counts the opened level files.
+type readCounter struct {
+	fstest.MapFS
+
+	mu    sync.Mutex
+	reads map[string]int
+}
+
+func (c *readCounter) Open(name string) (fs.File, error) {
+	if strings.HasSuffix(name, ".ldtkl") {
+		c.mu.Lock()
+		c.reads[name]++
+		c.mu.Unlock()
+	}
+
+	return c.MapFS.Open(name)
+}
+
+func (c *readCounter) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(struct{ fs.FS }{c}, name)
+}
+
+func (c *readCounter) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(c.MapFS, name)
+}
+
+func (c *readCounter) total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, reads := range c.reads {
+		n += reads
+	}
+
+	return n
+}
+
+// externalLevels returns a project of three levels side by side, stored in
+// external files. Level i holds the entity layer Layer_i and the entity
+// Door_i, and the door of level 2 is in the table of contents.
+func externalLevels() *readCounter {
+	sys := fstest.MapFS{}
+
+	levels := make([]string, 0)
+	for i := 0; i < 3; i++ {
+		rel := fmt.Sprintf("project/Level_%d.ldtkl", i)
+		levels = append(levels, fmt.Sprintf(
+			`{"identifier":"Level_%d","iid":"level-%d","uid":%d,"worldX":%d,"worldY":0,"worldDepth":0,"pxWid":16,"pxHei":16,"externalRelPath":%q,"layerInstances":null,"fieldInstances":[],"__neighbours":[]}`,
+			i, i, i, i*16, rel))
+
+		sys[rel] = &fstest.MapFile{Data: []byte(fmt.Sprintf(
+			`{"identifier":"Level_%[1]d","iid":"level-%[1]d","uid":%[1]d,"worldX":%[2]d,"worldY":0,"worldDepth":0,"pxWid":16,"pxHei":16,"fieldInstances":[],"__neighbours":[],"layerInstances":[`+
+				`{"__identifier":"Layer_%[1]d","__type":"Entities","__cWid":1,"__cHei":1,"__gridSize":16,"__opacity":1,"__pxTotalOffsetX":0,"__pxTotalOffsetY":0,"iid":"layer-%[1]d","levelId":%[1]d,"layerDefUid":1,"pxOffsetX":0,"pxOffsetY":0,"visible":true,"intGridCsv":[],"autoLayerTiles":[],"gridTiles":[],"entityInstances":[`+
+				`{"__identifier":"Door","__grid":[0,0],"__pivot":[0,0],"__tags":[],"__tile":null,"__worldX":%[2]d,"__worldY":0,"iid":"door-%[1]d","width":16,"height":16,"defUid":2,"px":[0,0],"fieldInstances":[]}]}]}`,
+			i, i*16))}
+	}
+
+	sys["project.ldtk"] = &fstest.MapFile{Data: []byte(`{"iid":"project","jsonVersion":"1.5.3","externalLevels":true,"worldLayout":"Free","dummyWorldIid":"world",` +
+		`"toc":[{"identifier":"Door","instances":[],"instancesData":[{"iids":{"entityIid":"door-2","layerIid":"layer-2","levelIid":"level-2","worldIid":"world"},"worldX":32,"worldY":0,"widPx":16,"heiPx":16,"fields":{}}]}],` +
+		`"defs":{"layers":[{"identifier":"Layer","type":"Entities","uid":1,"gridSize":16}],"entities":[{"identifier":"Door","uid":2,"width":16,"height":16}],"tilesets":[],"enums":[],"externalEnums":[],"levelFields":[]},` +
+		`"levels":[` + strings.Join(levels, ",") + `],"worlds":[]}`)}
+
+	return &readCounter{MapFS: sys, reads: make(map[string]int)}
+}
+
+func TestIndexExternalLevels(t *testing.T) {
+	sys := externalLevels()
+	r, err := Load(sys, "project.ldtk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := sys.total(); n != 0 {
+		t.Fatalf("Load() read %d level files, want none", n)
+	}
+
+	tests := []struct {
+		name   string
+		lookup func() bool
+		found  bool
+		reads  map[string]int
+	}{
+		{
+			name:   "unknown iid",
+			lookup: func() bool { _, ok := r.ByIid("missing"); return ok },
+			reads:  map[string]int{},
+		},
+		{
+			name:   "entity of a level never loaded",
+			lookup: func() bool { _, ok := r.EntityByIid("door-1"); return ok },
+			reads:  map[string]int{},
+		},
+		{
+			name:   "entity in the table of contents",
+			lookup: func() bool { _, ok := r.EntityByIid("door-2"); return ok },
+			found:  true,
+			reads:  map[string]int{"project/Level_2.ldtkl": 1},
+		},
+		{
+			name:   "layer of a loaded level",
+			lookup: func() bool { _, ok := r.LayerByIid("layer-2"); return ok },
+			found:  true,
+			reads:  map[string]int{"project/Level_2.ldtkl": 1},
+		},
+		{
+			name:   "level",
+			lookup: func() bool { _, ok := r.LevelByIid("level-0"); return ok },
+			found:  true,
+			reads:  map[string]int{"project/Level_0.ldtkl": 1, "project/Level_2.ldtkl": 1},
+		},
+		{
+			name:   "entity of a loaded level",
+			lookup: func() bool { _, ok := r.ByIid("door-0"); return ok },
+			found:  true,
+			reads:  map[string]int{"project/Level_0.ldtkl": 1, "project/Level_2.ldtkl": 1},
+		},
+		{
+			name: "layers of a loaded level",
+			lookup: func() bool {
+				lvl, ok := r.LevelByIid("level-0")
+				return ok && len(lvl.Layers()) == 1
+			},
+			found: true,
+			reads: map[string]int{"project/Level_0.ldtkl": 1, "project/Level_2.ldtkl": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if found := tt.lookup(); found != tt.found {
+				t.Errorf("found = %v, want %v", found, tt.found)
+			}
+
+			sys.mu.Lock()
+			defer sys.mu.Unlock()
+			if fmt.Sprint(sys.reads) != fmt.Sprint(tt.reads) {
+				t.Errorf("reads = %v, want %v", sys.reads, tt.reads)
+			}
+		})
+	}
+}
+
+func TestIndexRetriesFailedLoads(t *testing.T) {
+	sys := externalLevels()
+	r, err := Load(sys, "project.ldtk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := sys.MapFS["project/Level_1.ldtkl"].Data
+	sys.MapFS["project/Level_1.ldtkl"].Data = data[:len(data)/2]
+	if _, err := r.LoadLevel("level-1"); err == nil {
+		t.Fatal("LoadLevel() of a truncated file succeeded")
+	}
+
+	sys.MapFS["project/Level_1.ldtkl"].Data = data
+	lvl, err := r.LoadLevel("level-1")
+	if err != nil {
+		t.Fatalf("LoadLevel() after the file is saved again: %v", err)
+	}
+	if n := len(lvl.Layers()); n != 1 {
+		t.Errorf("LoadLevel() layers = %d, want 1", n)
+	}
+}
@@ -0,0 +1,60 @@
+package goldtk
+
+// IntGrid is the grid of integer values of an IntGrid layer. A value of 0 is
+// an empty cell.
+type IntGrid interface {
+	Width() int
+	Height() int
+
+	// At returns the value of the cell cx, cy, or 0 outside the grid.
+	At(cx, cy int) int
+
+	// Values returns every cell value, from left to right and top to bottom.
+	Values() []int
+}
+
+type intGrid struct {
+	width, height int
+	values        []int
+}
+
+func (g intGrid) Width() int {
+	return g.width
+}
+
+func (g intGrid) Height() int {
+	return g.height
+}
+
+func (g intGrid) At(cx, cy int) int {
+	if cx < 0 || cy < 0 || cx >= g.width || cy >= g.height {
+		return 0
+	}
+
+	i := cy*g.width + cx
+	if i >= len(g.values) {
+		return 0
+	}
+
+	return g.values[i]
+}
+
+func (g intGrid) Values() []int {
+	return g.values
+}
+
+// NewIntGrid creates a grid from its CSV values, in the `intGridCsv` order.
+func NewIntGrid(width, height int, csv []int64) IntGrid {
+	values := make([]int, len(csv))
+	for i, v := range csv {
+		values[i] = int(v)
+	}
+
+	return intGrid{
+		width:  width,
+		height: height,
+		values: values,
+	}
+}
+
+var _ IntGrid = intGrid{}
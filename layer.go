@@ -1,6 +1,7 @@
 package goldtk
 
 import (
+	"goldtk/maybe"
 	"goldtk/quicktype"
 )
 
@@ -8,9 +9,9 @@ type LayerType string
 
 const (
 	IntGridLayer LayerType = "IntGrid"
-	EntityLayer  LayerType = "Entity"
+	EntityLayer  LayerType = "Entities"
 	TilesLayer   LayerType = "Tiles"
-	AutoLayer    LayerType = "Auto"
+	AutoLayer    LayerType = "AutoLayer"
 )
 
 type Layer interface {
@@ -18,6 +19,10 @@ type Layer interface {
 	Iid() InstanceIdentifier
 	LayerDefUid() Uid
 
+	// Def returns the definition of the layer, or nil when the layer does not
+	// belong to a Root.
+	Def() LayerDef
+
 	Type() LayerType
 
 	GridWidth() int
@@ -25,24 +30,61 @@ type Layer interface {
 
 	GridSizeInPx() int
 
+	// PxTotalOffsetX and PxTotalOffsetY are the pixel offset of the layer
+	// origin from the level origin, including definition and instance offsets.
+	PxTotalOffsetX() int
+	PxTotalOffsetY() int
+
+	// Space returns the conversions between world, level, layer and grid
+	// coordinates for this layer.
+	Space() LayerSpace
+
 	Opacity() float32
 	IsVisible() bool
 
-	Tileset() quicktype.TilesetDefinition // TODO need a tileset
+	// TilesetUid is the unique ID of the tileset used by the layer tiles,
+	// taking the instance override into account.
+	TilesetUid() maybe.Value[int64]
 
-	AutoLayerTiles()
+	// Tileset returns the decoded tileset of the layer, when its Root could
+	// load it.
+	Tileset() maybe.Value[Tileset]
+
+	AutoLayerTiles() []Tile
 	Entities() []Entity
 	GridTiles() []Tile
-	IntGrid() []IntGrid
+	IntGrid() IntGrid
 }
 
 type layer struct {
 	inst quicktype.LayerInstance
+
+	// levelX and levelY are the world coordinates of the owning level.
+	levelX, levelY int
+
+	idx *index
+}
+
+func (l layer) TilesetUid() maybe.Value[int64] {
+	if l.inst.OverrideTilesetUid != nil {
+		return maybe.From[int64](l.inst.OverrideTilesetUid)
+	}
+
+	return maybe.From[int64](l.inst.TilesetDefUid)
 }
 
-func (l layer) Tileset() quicktype.TilesetDefinition {
-	//TODO implement me
-	panic("implement me")
+func (l layer) Tileset() maybe.Value[Tileset] {
+	uid, ok := l.TilesetUid().Get()
+	if !ok || l.idx == nil {
+		return maybe.From[Tileset](nil)
+	}
+
+	ts, err := l.idx.tileset(uid)
+	if err != nil {
+		return maybe.From[Tileset](nil)
+	}
+
+	return maybe.From(&ts)
 }
 
 func (l layer) Identifier() Identifier {
@@ -50,13 +92,26 @@ func (l layer) Identifier() Identifier {
 }
 
 func (l layer) Iid() InstanceIdentifier {
-	return InstanceIdentifier(l.inst.Identifier)
+	return InstanceIdentifier(l.inst.Iid)
 }
 
 func (l layer) LayerDefUid() Uid {
 	return Uid(l.inst.LayerDefUid)
 }
 
+func (l layer) Def() LayerDef {
+	if l.idx == nil {
+		return nil
+	}
+
+	def, ok := l.idx.layerDefs[l.inst.LayerDefUid]
+	if !ok {
+		return nil
+	}
+
+	return NewLayerDef(def)
+}
+
 func (l layer) Type() LayerType {
 	return LayerType(l.inst.Type)
 }
@@ -73,6 +128,24 @@ func (l layer) GridSizeInPx() int {
 	return int(l.inst.GridSize)
 }
 
+func (l layer) PxTotalOffsetX() int {
+	return int(l.inst.PxTotalOffsetX)
+}
+
+func (l layer) PxTotalOffsetY() int {
+	return int(l.inst.PxTotalOffsetY)
+}
+
+func (l layer) Space() LayerSpace {
+	return LayerSpace{
+		LevelWorldX: l.levelX,
+		LevelWorldY: l.levelY,
+		OffsetX:     l.PxTotalOffsetX(),
+		OffsetY:     l.PxTotalOffsetY(),
+		GridSize:    l.GridSizeInPx(),
+	}
+}
+
 func (l layer) Opacity() float32 {
 	return float32(l.inst.Opacity)
 }
@@ -81,34 +154,53 @@ func (l layer) IsVisible() bool {
 	return l.inst.Visible
 }
 
-func (l layer) AutoLayerTiles() {
-	//TODO implement me
-	panic("implement me")
+func (l layer) AutoLayerTiles() []Tile {
+	return l.tiles(l.inst.AutoLayerTiles)
 }
 
 func (l layer) Entities() []Entity {
 	entities := make([]Entity, 0)
 	for _, e := range l.inst.EntityInstances {
-		entities = append(entities, NewEntity(e))
+		entities = append(entities, newEntity(e, l.Space(), l.idx))
 	}
 
 	return entities
 }
 
 func (l layer) GridTiles() []Tile {
-	//TODO implement me
-	panic("implement me")
+	return l.tiles(l.inst.GridTiles)
 }
 
-func (l layer) IntGrid() []IntGrid {
-	//TODO implement me
-	panic("implement me")
+func (l layer) tiles(insts []quicktype.TileInstance) []Tile {
+	ts, _ := l.Tileset().Get()
+
+	tiles := make([]Tile, 0, len(insts))
+	for _, t := range insts {
+		tiles = append(tiles, NewTile(t, ts))
+	}
+
+	return tiles
+}
+
+func (l layer) IntGrid() IntGrid {
+	return NewIntGrid(l.GridWidth(), l.GridHeight(), l.inst.IntGridCSV)
 }
 
+// NewLayer wraps a layer instance. The layer is assumed to belong to a level
+// at the world origin; use Level.Layers to get layers placed in their world.
 func NewLayer(inst quicktype.LayerInstance) Layer {
 	return layer{
 		inst: inst,
 	}
 }
 
+func newLevelLayer(inst quicktype.LayerInstance, lvl quicktype.Level, idx *index) Layer {
+	return layer{
+		inst:   inst,
+		levelX: int(lvl.WorldX),
+		levelY: int(lvl.WorldY),
+		idx:    idx,
+	}
+}
+
 var _ Layer = layer{}
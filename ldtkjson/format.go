@@ -0,0 +1,117 @@
+package ldtkjson
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Canonicalize rewrites the tree v, a project or an external level, in its
+// canonical form: the keys of every object in the order the editor writes
+// them, and every number in its shortest form. Editor builds differ in both,
+// so that saving the same project from two builds gives the same canonical
+// form. Keys the editor does not write, such as those of newer builds, follow
+// the others in their order.
+func Canonicalize(v any) any {
+	kind := ""
+	if root, ok := v.(*Object); ok {
+		if _, ok := root.Get("defs"); !ok {
+			kind = "level file"
+		}
+	}
+
+	return canonicalize(v, kind)
+}
+
+// canonicalize rewrites v, held by the member or array kind.
+func canonicalize(v any, kind string) any {
+	switch v := v.(type) {
+	case []any:
+		for i, item := range v {
+			v[i] = canonicalize(item, kind)
+		}
+	case *Object:
+		if order, ok := editorKeyOrder[kind]; ok {
+			rank := func(key string) int {
+				if i := slices.Index(order, key); i >= 0 {
+					return i
+				}
+				return len(order)
+			}
+			slices.SortStableFunc(v.keys, func(a, b string) int {
+				return rank(a) - rank(b)
+			})
+		}
+		for _, key := range v.keys {
+			v.values[key] = canonicalize(v.values[key], key)
+		}
+	case json.Number:
+		return canonicalNumber(v)
+	}
+
+	return v
+}
+
+// canonicalNumber writes integers without fraction nor exponent, and other
+// numbers with the fewest digits which read back the same float64, the way
+// encoding/json writes them.
+func canonicalNumber(n json.Number) json.Number {
+	s := n.String()
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return json.Number(strconv.FormatInt(i, 10))
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) {
+		return n
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return json.Number(strconv.FormatInt(int64(f), 10))
+	}
+
+	abs := math.Abs(f)
+	if abs < 1e-6 || abs >= 1e21 {
+		s = strconv.FormatFloat(f, 'e', -1, 64)
+		// Clean up e-09 to e-9, like encoding/json.
+		if i := strings.Index(s, "e-0"); i >= 0 {
+			s = s[:i+2] + s[i+3:]
+		}
+		return json.Number(s)
+	}
+
+	return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// Format rewrites the LDtk file data in its canonical form, see Canonicalize,
+// minified or indented by two spaces.
+func Format(data []byte, minify bool) ([]byte, error) {
+	v, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	indent := "  "
+	if minify {
+		indent = ""
+	}
+
+	return Encode(Canonicalize(v), indent)
+}
+
+// Minified returns whether the LDtk file data is written minified: as set by
+// the "minifyJson" option of a project, or as found in a level file, which
+// has no such option.
+func Minified(data []byte) bool {
+	if tree, err := Decode(data); err == nil {
+		if root, ok := tree.(*Object); ok {
+			if v, ok := root.Get("minifyJson"); ok {
+				minify, _ := v.(bool)
+				return minify
+			}
+		}
+	}
+
+	return !strings.Contains(string(data), "\n")
+}
@@ -0,0 +1,175 @@
+package ldtkjson
+
+// editorKeyOrder lists the keys of the objects of LDtk files in the order the
+// editor writes them, as of 1.5.3. Objects are named after the key of the
+// member or array holding them, empty for the project and "level file" for
+// external levels. Objects missing from the table, such as user maps, keep
+// their order.
+var editorKeyOrder = map[string][]string{
+	"": {
+		"__header__", "iid", "jsonVersion", "appBuildId", "nextUid",
+		"identifierStyle", "toc", "worldLayout", "worldGridWidth",
+		"worldGridHeight", "defaultLevelWidth", "defaultLevelHeight",
+		"defaultPivotX", "defaultPivotY", "defaultGridSize",
+		"defaultEntityWidth", "defaultEntityHeight", "bgColor",
+		"defaultLevelBgColor", "minifyJson", "externalLevels", "exportTiled",
+		"simplifiedExport", "imageExportMode", "exportLevelBg",
+		"pngFilePattern", "backupOnSave", "backupLimit", "backupRelPath",
+		"levelNamePattern", "tutorialDesc", "customCommands", "flags", "defs",
+		"levels", "worlds", "dummyWorldIid",
+	},
+	"level file": {
+		"__header__",
+		"identifier", "iid", "uid", "worldX", "worldY", "worldDepth", "pxWid",
+		"pxHei", "__bgColor", "bgColor", "useAutoIdentifier", "bgRelPath",
+		"bgPos", "bgPivotX", "bgPivotY", "__smartColor", "__bgPos",
+		"externalRelPath", "fieldInstances", "layerInstances", "__neighbours",
+	},
+	"__header__": {
+		"fileType", "app", "doc", "schema", "appAuthor", "appVersion", "url",
+	},
+	"defs": {
+		"layers", "entities", "tilesets", "enums", "externalEnums",
+		"levelFields",
+	},
+	"layers": {
+		"__type", "identifier", "type", "uid", "doc", "uiColor", "gridSize",
+		"guideGridWid", "guideGridHei", "displayOpacity", "inactiveOpacity",
+		"hideInList", "hideFieldsWhenInactive", "canSelectWhenInactive",
+		"renderInWorldView", "pxOffsetX", "pxOffsetY", "parallaxFactorX",
+		"parallaxFactorY", "parallaxScaling", "requiredTags", "excludedTags",
+		"autoTilesKilledByOtherLayerUid", "uiFilterTags", "useAsyncRender",
+		"intGridValues", "intGridValuesGroups", "autoRuleGroups",
+		"autoSourceLayerDefUid", "tilesetDefUid", "tilePivotX", "tilePivotY",
+		"biomeFieldUid",
+	},
+	"intGridValues": {
+		"value", "identifier", "color", "tile", "groupUid",
+	},
+	"tile": {
+		"tilesetUid", "x", "y", "w", "h",
+	},
+	"autoRuleGroups": {
+		"uid", "name", "color", "icon", "active", "isOptional", "rules",
+		"usesWizard", "requiredBiomeValues", "biomeRequirementMode",
+	},
+	"rules": {
+		"uid", "active", "size", "tileRectsIds", "alpha", "chance",
+		"breakOnMatch", "pattern", "flipX", "flipY", "xModulo", "yModulo",
+		"xOffset", "yOffset", "tileXOffset", "tileYOffset", "tileRandomXMin",
+		"tileRandomXMax", "tileRandomYMin", "tileRandomYMax", "checker",
+		"tileMode", "pivotX", "pivotY", "outOfBoundsValue", "invalidated",
+		"perlinActive", "perlinSeed", "perlinScale", "perlinOctaves",
+	},
+	"entities": {
+		"identifier", "uid", "tags", "exportToToc", "allowOutOfBounds", "doc",
+		"width", "height", "resizableX", "resizableY", "minWidth", "maxWidth",
+		"minHeight", "maxHeight", "keepAspectRatio", "tileOpacity",
+		"fillOpacity", "lineOpacity", "hollow", "color", "renderMode",
+		"showName", "tilesetId", "tileRenderMode", "tileRect", "uiTileRect",
+		"nineSliceBorders", "maxCount", "limitScope", "limitBehavior",
+		"pivotX", "pivotY", "fieldDefs",
+	},
+	"tileRect": {
+		"tilesetUid", "x", "y", "w", "h",
+	},
+	"uiTileRect": {
+		"tilesetUid", "x", "y", "w", "h",
+	},
+	"fieldDefs": {
+		"identifier", "doc", "__type", "uid", "type", "isArray", "canBeNull",
+		"arrayMinLength", "arrayMaxLength", "editorDisplayMode",
+		"editorDisplayScale", "editorDisplayPos", "editorLinkStyle",
+		"editorDisplayColor", "editorAlwaysShow", "editorShowInWorld",
+		"editorCutLongValues", "editorTextSuffix", "editorTextPrefix",
+		"useForSmartColor", "exportToToc", "searchable", "min", "max", "regex",
+		"acceptFileTypes", "defaultOverride", "textLanguageMode",
+		"symmetricalRef", "autoChainRef", "allowOutOfLevelRef", "allowedRefs",
+		"allowedRefsEntityUid", "allowedRefTags", "tilesetUid",
+	},
+	"defaultOverride": {
+		"id", "params",
+	},
+	"tilesets": {
+		"__cWid", "__cHei", "identifier", "uid", "relPath", "embedAtlas",
+		"pxWid", "pxHei", "tileGridSize", "spacing", "padding", "tags",
+		"tagsSourceEnumUid", "enumTags", "customData", "savedSelections",
+		"cachedPixelData",
+	},
+	"cachedPixelData": {
+		"opaqueTiles", "averageColors",
+	},
+	"enums": {
+		"identifier", "uid", "values", "iconTilesetUid", "externalRelPath",
+		"externalFileChecksum", "tags",
+	},
+	"values": {
+		"id", "tileRect", "color",
+	},
+	"levelFields": {
+		"identifier", "doc", "__type", "uid", "type", "isArray", "canBeNull",
+		"arrayMinLength", "arrayMaxLength", "editorDisplayMode",
+		"editorDisplayScale", "editorDisplayPos", "editorLinkStyle",
+		"editorDisplayColor", "editorAlwaysShow", "editorShowInWorld",
+		"editorCutLongValues", "editorTextSuffix", "editorTextPrefix",
+		"useForSmartColor", "exportToToc", "searchable", "min", "max", "regex",
+		"acceptFileTypes", "defaultOverride", "textLanguageMode",
+		"symmetricalRef", "autoChainRef", "allowOutOfLevelRef", "allowedRefs",
+		"allowedRefsEntityUid", "allowedRefTags", "tilesetUid",
+	},
+	"levels": {
+		"identifier", "iid", "uid", "worldX", "worldY", "worldDepth", "pxWid",
+		"pxHei", "__bgColor", "bgColor", "useAutoIdentifier", "bgRelPath",
+		"bgPos", "bgPivotX", "bgPivotY", "__smartColor", "__bgPos",
+		"externalRelPath", "fieldInstances", "layerInstances", "__neighbours",
+	},
+	"fieldInstances": {
+		"__identifier", "__type", "__value", "__tile", "defUid",
+		"realEditorValues",
+	},
+	"realEditorValues": {
+		"id", "params",
+	},
+	"layerInstances": {
+		"__identifier", "__type", "__cWid", "__cHei", "__gridSize",
+		"__opacity", "__pxTotalOffsetX", "__pxTotalOffsetY", "__tilesetDefUid",
+		"__tilesetRelPath", "iid", "levelId", "layerDefUid", "pxOffsetX",
+		"pxOffsetY", "visible", "optionalRules", "intGridCsv",
+		"autoLayerTiles", "seed", "overrideTilesetUid", "gridTiles",
+		"entityInstances",
+	},
+	"entityInstances": {
+		"__identifier", "__grid", "__pivot", "__tags", "__tile",
+		"__smartColor", "iid", "width", "height", "defUid", "px",
+		"fieldInstances", "__worldX", "__worldY",
+	},
+	"__tile": {
+		"tilesetUid", "x", "y", "w", "h",
+	},
+	"__value": {
+		"entityIid", "layerIid", "levelIid", "worldIid", "cx", "cy",
+		"tilesetUid", "x", "y", "w", "h",
+	},
+	"autoLayerTiles": {
+		"px", "src", "f", "t", "d", "a",
+	},
+	"toc": {
+		"identifier", "instances", "instancesData",
+	},
+	"instancesData": {
+		"iids", "worldX", "worldY", "widPx", "heiPx", "fields",
+	},
+	"iids": {
+		"worldIid", "levelIid", "layerIid", "entityIid",
+	},
+	"__neighbours": {
+		"levelIid", "dir",
+	},
+	"externalEnums": {
+		"identifier", "uid", "values", "iconTilesetUid", "externalRelPath",
+		"externalFileChecksum", "tags",
+	},
+	"gridTiles": {
+		"px", "src", "f", "t", "d", "a",
+	},
+}
@@ -0,0 +1,215 @@
+// Package ldtkjson reads and writes LDtk files as trees of JSON values which
+// keep the order of object keys and the text of numbers, so that tools can
+// edit projects without rewriting what they do not touch.
+//
+// Values of a tree are nil, bool, string, json.Number, []any and *Object.
+package ldtkjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Object is a JSON object which keeps the order of its keys.
+type Object struct {
+	keys   []string
+	values map[string]any
+}
+
+// NewObject creates an empty object.
+func NewObject() *Object {
+	return &Object{values: make(map[string]any)}
+}
+
+// Keys returns the keys of the object, in order.
+func (o *Object) Keys() []string {
+	return o.keys
+}
+
+func (o *Object) Len() int {
+	return len(o.keys)
+}
+
+func (o *Object) Get(key string) (any, bool) {
+	v, ok := o.values[key]
+	return v, ok
+}
+
+// Set replaces the value of key, or adds key last.
+func (o *Object) Set(key string, v any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
+
+func (o *Object) Delete(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Decode reads a JSON document as a tree.
+func Decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	v, err := decodeValue(dec)
+	if err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+
+	if _, err := dec.Token(); err == nil {
+		return nil, fmt.Errorf("decoding JSON: unexpected data after the document")
+	}
+
+	return v, nil
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		o := NewObject()
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := tok.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %v", tok)
+			}
+
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			o.Set(key, v)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+
+		return o, nil
+
+	case json.Delim('['):
+		items := make([]any, 0)
+		for dec.More() {
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+
+		return items, nil
+	}
+
+	return tok, nil
+}
+
+// Encode writes a tree as JSON, with every object member and array item on
+// its own line when indent is not empty, or without any space otherwise. Like
+// the editor, characters such as < and > are not escaped, and the document
+// does not end with a newline.
+func Encode(v any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeValue(&buf, v, indent, 0); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeValue(buf *bytes.Buffer, v any, indent string, depth int) error {
+	newline := func(depth int) {
+		if indent != "" {
+			buf.WriteByte('\n')
+			buf.WriteString(strings.Repeat(indent, depth))
+		}
+	}
+
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		fmt.Fprint(buf, v)
+	case json.Number:
+		buf.WriteString(v.String())
+	case string:
+		buf.WriteString(quote(v))
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(depth + 1)
+			if err := encodeValue(buf, item, indent, depth+1); err != nil {
+				return err
+			}
+		}
+		newline(depth)
+		buf.WriteByte(']')
+	case *Object:
+		if v.Len() == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+
+		buf.WriteByte('{')
+		for i, key := range v.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(depth + 1)
+			buf.WriteString(quote(key))
+			buf.WriteByte(':')
+			if indent != "" {
+				buf.WriteByte(' ')
+			}
+			if err := encodeValue(buf, v.values[key], indent, depth+1); err != nil {
+				return err
+			}
+		}
+		newline(depth)
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("encoding JSON: unsupported value %T", v)
+	}
+
+	return nil
+}
+
+// quote returns s as a JSON string, without escaping HTML characters.
+func quote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -1,6 +1,9 @@
 package goldtk
 
-import "goldtk/quicktype"
+import (
+	"goldtk/maybe"
+	"goldtk/quicktype"
+)
 
 type Level interface {
 	Identifier() Identifier
@@ -19,13 +22,16 @@ type Level interface {
 	Layers() []Layer
 	Neighbours() []Neighbor
 
-	ExternalRelPath() // TODO
+	// ExternalRelPath is the path of the file holding the level layers, when
+	// the project saves levels separately.
+	ExternalRelPath() maybe.Value[string]
 
 	Fields() []Field
 }
 
 type level struct {
 	inst quicktype.Level
+	idx  *index
 }
 
 func (l level) Identifier() Identifier {
@@ -33,7 +39,7 @@ func (l level) Identifier() Identifier {
 }
 
 func (l level) Iid() InstanceIdentifier {
-	return InstanceIdentifier(l.inst.Identifier)
+	return InstanceIdentifier(l.inst.Iid)
 }
 
 func (l level) Uid() Uid {
@@ -64,10 +70,21 @@ func (l level) WorldDepth() int {
 	return int(l.inst.WorldDepth)
 }
 
+// Layers returns the layers of the level. Levels stored in external files are
+// loaded on first access when the level belongs to a Root, and have no layers
+// when their file cannot be read, see Root.LoadLevel. A level obtained once
+// loaded keeps its layers, even when the Root unloads them.
 func (l level) Layers() []Layer {
+	inst := l.inst
+	if l.idx != nil && inst.LayerInstances == nil {
+		if loaded, err := l.idx.levelInst(l.inst.Iid); err == nil {
+			inst = loaded
+		}
+	}
+
 	layers := make([]Layer, 0)
-	for _, lyr := range l.inst.LayerInstances {
-		layers = append(layers, NewLayer(lyr))
+	for _, lyr := range inst.LayerInstances {
+		layers = append(layers, newLevelLayer(lyr, inst, l.idx))
 	}
 
 	return layers
@@ -82,22 +99,25 @@ func (l level) Neighbours() []Neighbor {
 	return neighbors
 }
 
-func (l level) ExternalRelPath() {
-	//TODO implement me
-	panic("implement me")
+func (l level) ExternalRelPath() maybe.Value[string] {
+	return maybe.From[string](l.inst.ExternalRelPath)
 }
 
 func (l level) Fields() []Field {
 	fields := make([]Field, 0)
 	for _, f := range l.inst.FieldInstances {
-		fields = append(fields, NewField(f))
+		fields = append(fields, newField(f, l.idx))
 	}
 
 	return fields
 }
 
 func NewLevel(inst quicktype.Level) Level {
-	return level{inst}
+	return level{inst: inst}
+}
+
+func newLevel(inst quicktype.Level, idx *index) Level {
+	return level{inst: inst, idx: idx}
 }
 
 var _ Level = level{}
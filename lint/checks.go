@@ -0,0 +1,261 @@
+package lint
+
+import (
+	"fmt"
+	"goldtk"
+	"goldtk/quicktype"
+	"regexp"
+	"strings"
+)
+
+// EntityBounds reports entities extending outside of their level, when their
+// definition does not allow it.
+var EntityBounds Check = check{name: "entity-bounds", run: entityBounds}
+
+// EntityLimits reports entities whose count exceeds the MaxCount of their
+// definition, within its LimitScope.
+var EntityLimits Check = check{name: "entity-limits", run: entityLimits}
+
+// FieldConstraints reports entity and level field values violating the
+// Min, Max, Regex, ArrayMinLength, ArrayMaxLength or CanBeNull settings of
+// their definition.
+var FieldConstraints Check = check{name: "field-constraints", run: fieldConstraints}
+
+// TileIDs reports layer tiles whose ID is outside of their tileset grid.
+var TileIDs Check = check{name: "tile-ids", run: tileIDs}
+
+// IntGridValues reports IntGrid cells holding a value the layer definition
+// does not declare.
+var IntGridValues Check = check{name: "intgrid-values", run: intGridValues}
+
+func entityBounds(r goldtk.Root, report reporter) {
+	for _, lvl := range r.Levels() {
+		bounds := goldtk.LevelRect{W: lvl.PxWidth(), H: lvl.PxHeight()}
+
+		for _, lyr := range lvl.Layers() {
+			for _, e := range lyr.Entities() {
+				def := e.Def()
+				if def == nil || def.AllowOutOfBounds() {
+					continue
+				}
+
+				b := e.LevelBounds()
+				if b.X < bounds.X || b.Y < bounds.Y || b.X+b.W > bounds.W || b.Y+b.H > bounds.H {
+					report(Error, Location{Level: lvl.Iid(), Layer: lyr.Iid(), Entity: e.Iid()},
+						"%s at %d,%d (%dx%d) is outside of level %s (%dx%d)",
+						e.Identifier(), b.X, b.Y, b.W, b.H, lvl.Identifier(), bounds.W, bounds.H)
+				}
+			}
+		}
+	}
+}
+
+func entityLimits(r goldtk.Root, report reporter) {
+	type scope struct {
+		def goldtk.Uid
+		iid goldtk.InstanceIdentifier
+	}
+
+	counts := make(map[scope]int)
+	locations := make(map[scope]Location)
+	defs := make(map[scope]goldtk.EntityDef)
+	order := make([]scope, 0)
+
+	for _, w := range r.Worlds() {
+		for _, lvl := range w.Levels() {
+			for _, lyr := range lvl.Layers() {
+				for _, e := range lyr.Entities() {
+					def := e.Def()
+					if def == nil || def.MaxCount() <= 0 {
+						continue
+					}
+
+					var s scope
+					var loc Location
+					switch def.LimitScope() {
+					case quicktype.PerLayer:
+						s, loc = scope{def.Uid(), lyr.Iid()}, Location{Level: lvl.Iid(), Layer: lyr.Iid()}
+					case quicktype.PerWorld:
+						s, loc = scope{def.Uid(), w.Iid()}, Location{World: w.Iid()}
+					default:
+						s, loc = scope{def.Uid(), lvl.Iid()}, Location{Level: lvl.Iid()}
+					}
+
+					if _, ok := counts[s]; !ok {
+						order = append(order, s)
+						locations[s] = loc
+						defs[s] = def
+					}
+					counts[s]++
+				}
+			}
+		}
+	}
+
+	for _, s := range order {
+		def := defs[s]
+		if counts[s] > def.MaxCount() {
+			report(Error, locations[s], "%d %s entities exceed the limit of %d (%s)",
+				counts[s], def.Identifier(), def.MaxCount(), def.LimitScope())
+		}
+	}
+}
+
+func fieldConstraints(r goldtk.Root, report reporter) {
+	for _, lvl := range r.Levels() {
+		for _, f := range lvl.Fields() {
+			checkField(f, Location{Level: lvl.Iid(), Field: f.Identifier()}, report)
+		}
+
+		for _, lyr := range lvl.Layers() {
+			for _, e := range lyr.Entities() {
+				for _, f := range e.Fields() {
+					checkField(f, Location{Level: lvl.Iid(), Layer: lyr.Iid(), Entity: e.Iid(), Field: f.Identifier()}, report)
+				}
+			}
+		}
+	}
+}
+
+func checkField(f goldtk.Field, loc Location, report reporter) {
+	def := f.Def()
+	if def == nil {
+		return
+	}
+
+	if !def.IsArray() {
+		checkFieldValue(def, f.Value(), "", loc, report)
+		return
+	}
+
+	items := f.Value().Array()
+	if min, ok := def.ArrayMinLength().Get(); ok && int64(len(items)) < min {
+		report(Error, loc, "array has %d values, less than the minimum of %d", len(items), min)
+	}
+	if max, ok := def.ArrayMaxLength().Get(); ok && int64(len(items)) > max {
+		report(Error, loc, "array has %d values, more than the maximum of %d", len(items), max)
+	}
+
+	for i, item := range items {
+		checkFieldValue(def, item, fmt.Sprintf("value #%d ", i), loc, report)
+	}
+}
+
+func checkFieldValue(def goldtk.FieldDef, v goldtk.FieldValue, prefix string, loc Location, report reporter) {
+	if v.IsNull() {
+		if !def.CanBeNull() {
+			report(Error, loc, "%sis null, but the field cannot be null", prefix)
+		}
+		return
+	}
+
+	typ := strings.TrimSuffix(strings.TrimPrefix(def.Type(), "Array<"), ">")
+
+	var number float64
+	switch typ {
+	case "Int":
+		number = float64(v.Int())
+	case "Float":
+		number = v.Float64()
+	case "String":
+		pattern, ok := def.Regex().Get()
+		if !ok {
+			return
+		}
+
+		re, err := compileRegex(pattern)
+		if err != nil {
+			report(Warning, loc, "regex %s cannot be checked: %v", pattern, err)
+			return
+		}
+		if !re.MatchString(v.String()) {
+			report(Error, loc, "%s%q does not match %s", prefix, v.String(), pattern)
+		}
+		return
+	default:
+		return
+	}
+
+	if min, ok := def.Min().Get(); ok && number < min {
+		report(Error, loc, "%s%v is less than the minimum of %v", prefix, number, min)
+	}
+	if max, ok := def.Max().Get(); ok && number > max {
+		report(Error, loc, "%s%v is more than the maximum of %v", prefix, number, max)
+	}
+}
+
+// compileRegex compiles a field regex, which LDtk stores in the JavaScript
+// `/pattern/flags` form.
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	flags := ""
+	if strings.HasPrefix(pattern, "/") {
+		end := strings.LastIndex(pattern, "/")
+		if end > 0 {
+			pattern, flags = pattern[1:end], pattern[end+1:]
+		}
+	}
+
+	if strings.Contains(flags, "i") {
+		pattern = "(?i)" + pattern
+	}
+
+	return regexp.Compile(pattern)
+}
+
+func tileIDs(r goldtk.Root, report reporter) {
+	for _, lvl := range r.Levels() {
+		for _, lyr := range lvl.Layers() {
+			uid, ok := lyr.TilesetUid().Get()
+			if !ok {
+				continue
+			}
+
+			def, ok := r.TilesetDefByUid(goldtk.Uid(uid))
+			if !ok {
+				report(Error, Location{Level: lvl.Iid(), Layer: lyr.Iid()}, "tileset %d does not exist", uid)
+				continue
+			}
+
+			count := int(def.CWid * def.CHei)
+			space := lyr.Space()
+			tiles := append(lyr.GridTiles(), lyr.AutoLayerTiles()...)
+
+			for _, t := range tiles {
+				if t.ID() >= 0 && t.ID() < count {
+					continue
+				}
+
+				cell := space.LayerToGrid(goldtk.LayerPoint{X: t.X(), Y: t.Y()})
+				report(Error, Location{Level: lvl.Iid(), Layer: lyr.Iid(), Cell: &cell},
+					"tile %d is outside of tileset %s, which has %d tiles", t.ID(), def.Identifier, count)
+			}
+		}
+	}
+}
+
+func intGridValues(r goldtk.Root, report reporter) {
+	for _, lvl := range r.Levels() {
+		for _, lyr := range lvl.Layers() {
+			def := lyr.Def()
+			if lyr.Type() != goldtk.IntGridLayer || def == nil {
+				continue
+			}
+
+			grid := lyr.IntGrid()
+			for cy := 0; cy < grid.Height(); cy++ {
+				for cx := 0; cx < grid.Width(); cx++ {
+					v := grid.At(cx, cy)
+					if v == 0 {
+						continue
+					}
+
+					if _, ok := def.IntGridValue(v); !ok {
+						cell := goldtk.GridPoint{CX: cx, CY: cy}
+						report(Error, Location{Level: lvl.Iid(), Layer: lyr.Iid(), Cell: &cell},
+							"value %d is not defined by layer %s", v, def.Identifier())
+					}
+				}
+			}
+		}
+	}
+}
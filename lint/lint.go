@@ -0,0 +1,126 @@
+// Package lint checks the content of LDtk projects against the constraints
+// set by their definitions, beyond what the JSON schema can express.
+package lint
+
+import (
+	"fmt"
+	"goldtk"
+)
+
+// Severity is the importance of a Diagnostic.
+type Severity string
+
+const (
+	// Error is a finding the LDtk editor itself would refuse, or which makes
+	// the data unusable.
+	Error Severity = "error"
+
+	// Warning is a finding which is likely a mistake.
+	Warning Severity = "warning"
+)
+
+// Location points to the part of a project a Diagnostic is about. Fields are
+// left empty when they do not apply.
+type Location struct {
+	World  goldtk.InstanceIdentifier
+	Level  goldtk.InstanceIdentifier
+	Layer  goldtk.InstanceIdentifier
+	Entity goldtk.InstanceIdentifier
+	Field  goldtk.Identifier
+
+	// Cell is the layer grid cell, for IntGrid and tile findings.
+	Cell *goldtk.GridPoint
+}
+
+func (l Location) String() string {
+	s := ""
+	add := func(name, value string) {
+		if value == "" {
+			return
+		}
+		if s != "" {
+			s += " "
+		}
+		s += name + "=" + value
+	}
+
+	add("world", string(l.World))
+	add("level", string(l.Level))
+	add("layer", string(l.Layer))
+	add("entity", string(l.Entity))
+	add("field", string(l.Field))
+	if l.Cell != nil {
+		add("cell", fmt.Sprintf("%d,%d", l.Cell.CX, l.Cell.CY))
+	}
+
+	return s
+}
+
+// Diagnostic is a single finding of a Check.
+type Diagnostic struct {
+	Check    string
+	Severity Severity
+	Location Location
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", d.Severity, d.Check, d.Location, d.Message)
+}
+
+// Check inspects a project and reports its findings.
+type Check interface {
+	Name() string
+	Run(r goldtk.Root) []Diagnostic
+}
+
+type check struct {
+	name string
+	run  func(r goldtk.Root, report reporter)
+}
+
+func (c check) Name() string {
+	return c.name
+}
+
+func (c check) Run(r goldtk.Root) []Diagnostic {
+	diags := make([]Diagnostic, 0)
+	c.run(r, func(severity Severity, loc Location, format string, args ...any) {
+		diags = append(diags, Diagnostic{
+			Check:    c.name,
+			Severity: severity,
+			Location: loc,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	})
+
+	return diags
+}
+
+var _ Check = check{}
+
+type reporter func(severity Severity, loc Location, format string, args ...any)
+
+// Checks lists every available check, in the order Run applies them.
+var Checks = []Check{
+	EntityBounds,
+	EntityLimits,
+	FieldConstraints,
+	TileIDs,
+	IntGridValues,
+}
+
+// Run applies the checks to the project, or every check of Checks when none
+// is given, and returns their diagnostics.
+func Run(r goldtk.Root, checks ...Check) []Diagnostic {
+	if len(checks) == 0 {
+		checks = Checks
+	}
+
+	diags := make([]Diagnostic, 0)
+	for _, c := range checks {
+		diags = append(diags, c.Run(r)...)
+	}
+
+	return diags
+}
@@ -45,7 +45,7 @@ func (n nullable[T]) Get() (T, bool) {
 		v = *n.v
 	}
 
-	return v, n.v == nil
+	return v, n.v != nil
 }
 
 var _ Value[struct{}] = nullable[struct{}]{}
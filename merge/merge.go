@@ -0,0 +1,333 @@
+// Package merge combines two versions of an LDtk project, or of an external
+// level file, edited from a common ancestor. Instances are matched by their
+// instance identifier and definitions by their unique identifier, so edits
+// of different levels, entities or definitions merge on their own.
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"goldtk/ldtkjson"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Conflict is a value changed differently by both sides. The merged file
+// keeps our side.
+type Conflict struct {
+	// Path locates the value, with array items named by their identifier or
+	// their instance or unique identifier.
+	Path string
+
+	// Base, Ours and Theirs are the JSON values of each version, nil when
+	// missing.
+	Base   any
+	Ours   any
+	Theirs any
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: base %s, ours %s, theirs %s", c.Path, format(c.Base), format(c.Ours), format(c.Theirs))
+}
+
+// Result is the outcome of Merge.
+type Result struct {
+	// Data is the merged file, minified when ours is, see
+	// ldtkjson.Minified.
+	Data []byte
+
+	// Conflicts lists the values kept from our side although their side
+	// changed them too.
+	Conflicts []Conflict
+
+	// Reallocated maps the unique identifiers created by their side, which
+	// ours created too for something else, to the ones they were given.
+	Reallocated map[int64]int64
+}
+
+// Merge merges the changes from base to ours and from base to theirs.
+//
+// Both sides allocate unique identifiers from the same `nextUid`, so that
+// definitions created on each side may share one. Their definitions are
+// then given new unique identifiers, along with every reference to them, and
+// `nextUid` is raised past every allocated identifier. External level files
+// are merged the same way, but without reallocation: Merge fails when a
+// project with external levels needs one, as the level files of their side
+// would keep referencing the previous identifiers.
+func Merge(base, ours, theirs []byte) (Result, error) {
+	var trees [3]any
+	for i, data := range [][]byte{base, ours, theirs} {
+		tree, err := ldtkjson.Decode(data)
+		if err != nil {
+			return Result{}, fmt.Errorf("reading %s: %w", [...]string{"base", "ours", "theirs"}[i], err)
+		}
+		trees[i] = tree
+	}
+	b, o, t := trees[0], trees[1], trees[2]
+
+	result := Result{Reallocated: reallocate(b, o, t)}
+	if len(result.Reallocated) > 0 && (externalLevels(o) || externalLevels(t)) {
+		return result, fmt.Errorf("their definitions %s need new uids, which their external level files would not follow: merge the definitions by hand", reallocatedUids(result.Reallocated))
+	}
+
+	m := &merger{conflicts: make([]Conflict, 0)}
+	merged := m.merge("", b, o, t)
+	result.Conflicts = m.conflicts
+
+	indent := "  "
+	if ldtkjson.Minified(ours) {
+		indent = ""
+	}
+
+	data, err := ldtkjson.Encode(merged, indent)
+	if err != nil {
+		return Result{}, err
+	}
+	result.Data = data
+
+	return result, nil
+}
+
+// absent marks a key or an array item missing from a version.
+type absent struct{}
+
+type merger struct {
+	conflicts []Conflict
+}
+
+// merge returns the merged value, or absent when removed.
+func (m *merger) merge(path string, b, o, t any) any {
+	switch {
+	case reflect.DeepEqual(o, t):
+		return o
+	case reflect.DeepEqual(b, o):
+		return t
+	case reflect.DeepEqual(b, t):
+		return o
+	}
+
+	if oo, ok := o.(*ldtkjson.Object); ok {
+		if to, ok := t.(*ldtkjson.Object); ok {
+			bo, ok := b.(*ldtkjson.Object)
+			if !ok {
+				bo = ldtkjson.NewObject()
+			}
+			return m.mergeObject(path, bo, oo, to)
+		}
+	}
+
+	if oa, ok := o.([]any); ok {
+		if ta, ok := t.([]any); ok {
+			ba, ok := b.([]any)
+			if !ok {
+				ba = make([]any, 0)
+			}
+
+			if key, ok := identityKey(ba, oa, ta); ok {
+				return m.mergeItems(path, key, ba, oa, ta)
+			}
+
+			// IntGrid cells are merged one by one, so that painting different
+			// parts of a layer does not conflict.
+			if strings.HasSuffix(path, "/intGridCsv") && len(oa) == len(ta) && (len(ba) == len(oa) || len(ba) == 0) {
+				cells := make([]any, len(oa))
+				for i := range oa {
+					var bc any = absent{}
+					if len(ba) > 0 {
+						bc = ba[i]
+					}
+					cells[i] = m.merge(fmt.Sprintf("%s/%d", path, i), bc, oa[i], ta[i])
+				}
+				return cells
+			}
+		}
+	}
+
+	m.conflict(path, b, o, t)
+	return o
+}
+
+// conflict records a conflict. Values under keys starting with `__` are
+// computed by the editor from other values, whose conflicts are reported
+// instead.
+func (m *merger) conflict(path string, b, o, t any) {
+	if strings.Contains(path, "/__") {
+		return
+	}
+
+	value := func(v any) any {
+		if _, ok := v.(absent); ok {
+			return nil
+		}
+		return v
+	}
+
+	m.conflicts = append(m.conflicts, Conflict{Path: path, Base: value(b), Ours: value(o), Theirs: value(t)})
+}
+
+// mergeObject merges the members of objects, keeping the order of ours and
+// adding the keys only theirs has last.
+func (m *merger) mergeObject(path string, b, o, t *ldtkjson.Object) *ldtkjson.Object {
+	keys := slices.Clone(o.Keys())
+	for _, key := range t.Keys() {
+		if _, ok := o.Get(key); !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	get := func(obj *ldtkjson.Object, key string) any {
+		if v, ok := obj.Get(key); ok {
+			return v
+		}
+		return absent{}
+	}
+
+	merged := ldtkjson.NewObject()
+	for _, key := range keys {
+		v := m.merge(path+"/"+key, get(b, key), get(o, key), get(t, key))
+		if _, ok := v.(absent); !ok {
+			merged.Set(key, v)
+		}
+	}
+
+	return merged
+}
+
+// identityKeys are the keys identifying the items of arrays, by preference.
+var identityKeys = []string{"iid", "uid", "defUid", "identifier"}
+
+// identityKey returns the key identifying the items of the arrays, which
+// every item holds with a value unique in its array.
+func identityKey(arrays ...[]any) (string, bool) {
+	if len(arrays[1]) == 0 && len(arrays[2]) == 0 {
+		return "", false
+	}
+
+outer:
+	for _, key := range identityKeys {
+		for _, items := range arrays {
+			seen := make(map[string]bool, len(items))
+			for _, item := range items {
+				id, ok := identity(item, key)
+				if !ok || seen[id] {
+					continue outer
+				}
+				seen[id] = true
+			}
+		}
+
+		return key, true
+	}
+
+	return "", false
+}
+
+// identity returns the value of key of an object item.
+func identity(item any, key string) (string, bool) {
+	obj, ok := item.(*ldtkjson.Object)
+	if !ok {
+		return "", false
+	}
+
+	v, ok := obj.Get(key)
+	if !ok {
+		return "", false
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	}
+
+	return "", false
+}
+
+// mergeItems merges arrays of items identified by key. Items keep the order
+// of ours, and items only theirs has follow the item they follow in theirs.
+func (m *merger) mergeItems(path, key string, b, o, t []any) []any {
+	index := func(items []any) map[string]any {
+		byID := make(map[string]any, len(items))
+		for _, item := range items {
+			id, _ := identity(item, key)
+			byID[id] = item
+		}
+		return byID
+	}
+	bi, oi, ti := index(b), index(o), index(t)
+
+	get := func(byID map[string]any, id string) any {
+		if v, ok := byID[id]; ok {
+			return v
+		}
+		return absent{}
+	}
+
+	merged := make([]any, 0, len(o))
+	ids := make([]string, 0, len(o))
+	for _, item := range o {
+		id, _ := identity(item, key)
+		v := m.merge(path+label(item, id), get(bi, id), item, get(ti, id))
+		if _, ok := v.(absent); !ok {
+			merged = append(merged, v)
+			ids = append(ids, id)
+		}
+	}
+
+	after := -1
+	for _, item := range t {
+		id, _ := identity(item, key)
+		if i := slices.Index(ids, id); i >= 0 {
+			after = i
+			continue
+		}
+		if _, ok := oi[id]; ok {
+			// Removed while merging the items of ours.
+			continue
+		}
+
+		v := m.merge(path+label(item, id), get(bi, id), absent{}, item)
+		if _, ok := v.(absent); ok {
+			continue
+		}
+
+		after++
+		merged = slices.Insert(merged, after, v)
+		ids = slices.Insert(ids, after, id)
+	}
+
+	return merged
+}
+
+// label names an array item in conflict paths.
+func label(item any, id string) string {
+	obj := item.(*ldtkjson.Object)
+	if v, ok := obj.Get("identifier"); ok {
+		if s, ok := v.(string); ok && s != id {
+			return "[" + s + "]"
+		}
+	}
+	if v, ok := obj.Get("__identifier"); ok {
+		if s, ok := v.(string); ok {
+			return "[" + s + " " + id + "]"
+		}
+	}
+
+	return "[" + id + "]"
+}
+
+// format renders a JSON value for conflict reports.
+func format(v any) string {
+	if v == nil {
+		return "null"
+	}
+
+	data, err := ldtkjson.Encode(v, "")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
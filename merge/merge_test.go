@@ -0,0 +1,146 @@
+package merge
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		conflicts          []string
+		reallocated        map[int64]int64
+		wantErr            bool
+	}{
+		{
+			name:   "different levels",
+			base:   `{"levels":[{"iid":"a","identifier":"A","worldX":0},{"iid":"b","identifier":"B","worldX":0}]}`,
+			ours:   `{"levels":[{"iid":"a","identifier":"A","worldX":1},{"iid":"b","identifier":"B","worldX":0}]}`,
+			theirs: `{"levels":[{"iid":"a","identifier":"A","worldX":0},{"iid":"b","identifier":"B","worldX":2}]}`,
+			want:   `{"levels":[{"iid":"a","identifier":"A","worldX":1},{"iid":"b","identifier":"B","worldX":2}]}`,
+		},
+		{
+			name:   "levels added on both sides",
+			base:   `{"levels":[{"iid":"a","identifier":"A"}]}`,
+			ours:   `{"levels":[{"iid":"a","identifier":"A"},{"iid":"b","identifier":"B"}]}`,
+			theirs: `{"levels":[{"iid":"a","identifier":"A"},{"iid":"c","identifier":"C"}]}`,
+			want:   `{"levels":[{"iid":"a","identifier":"A"},{"iid":"c","identifier":"C"},{"iid":"b","identifier":"B"}]}`,
+		},
+		{
+			name:      "same value changed",
+			base:      `{"levels":[{"iid":"a","identifier":"A","worldX":0}]}`,
+			ours:      `{"levels":[{"iid":"a","identifier":"A","worldX":1}]}`,
+			theirs:    `{"levels":[{"iid":"a","identifier":"A","worldX":2}]}`,
+			want:      `{"levels":[{"iid":"a","identifier":"A","worldX":1}]}`,
+			conflicts: []string{"/levels[A]/worldX: base 0, ours 1, theirs 2"},
+		},
+		{
+			name:      "removed and changed",
+			base:      `{"levels":[{"iid":"a","identifier":"A","worldX":0}]}`,
+			ours:      `{"levels":[]}`,
+			theirs:    `{"levels":[{"iid":"a","identifier":"A","worldX":2}]}`,
+			want:      `{"levels":[]}`,
+			conflicts: []string{`/levels[A]: base {"iid":"a","identifier":"A","worldX":0}, ours null, theirs {"iid":"a","identifier":"A","worldX":2}`},
+		},
+		{
+			name:   "computed values",
+			base:   `{"levels":[{"iid":"a","__neighbours":0}]}`,
+			ours:   `{"levels":[{"iid":"a","__neighbours":1}]}`,
+			theirs: `{"levels":[{"iid":"a","__neighbours":2}]}`,
+			want:   `{"levels":[{"iid":"a","__neighbours":1}]}`,
+		},
+		{
+			name:   "IntGrid cells",
+			base:   `{"layerInstances":[{"iid":"l","intGridCsv":[0,0,0]}]}`,
+			ours:   `{"layerInstances":[{"iid":"l","intGridCsv":[1,0,0]}]}`,
+			theirs: `{"layerInstances":[{"iid":"l","intGridCsv":[0,0,2]}]}`,
+			want:   `{"layerInstances":[{"iid":"l","intGridCsv":[1,0,2]}]}`,
+		},
+		{
+			name:        "definitions sharing a uid",
+			base:        `{"nextUid":10,"defs":{"entities":[]},"levels":[]}`,
+			ours:        `{"nextUid":11,"defs":{"entities":[{"uid":10,"identifier":"Door"}]},"levels":[]}`,
+			theirs:      `{"nextUid":11,"defs":{"entities":[{"uid":10,"identifier":"Key"}]},"levels":[{"iid":"a","layerInstances":[{"iid":"l","entityInstances":[{"iid":"e","defUid":10}]}]}]}`,
+			want:        `{"nextUid":12,"defs":{"entities":[{"uid":11,"identifier":"Key"},{"uid":10,"identifier":"Door"}]},"levels":[{"iid":"a","layerInstances":[{"iid":"l","entityInstances":[{"iid":"e","defUid":11}]}]}]}`,
+			reallocated: map[int64]int64{10: 11},
+		},
+		{
+			name:        "same definition on both sides",
+			base:        `{"nextUid":10,"defs":{"entities":[]}}`,
+			ours:        `{"nextUid":11,"defs":{"entities":[{"uid":10,"identifier":"Door"}]}}`,
+			theirs:      `{"nextUid":11,"defs":{"entities":[{"uid":10,"identifier":"Door"}]}}`,
+			want:        `{"nextUid":11,"defs":{"entities":[{"uid":10,"identifier":"Door"}]}}`,
+			reallocated: map[int64]int64{},
+		},
+		{
+			name:    "definitions sharing a uid with external levels",
+			base:    `{"externalLevels":true,"nextUid":10,"defs":{"entities":[]}}`,
+			ours:    `{"externalLevels":true,"nextUid":11,"defs":{"entities":[{"uid":10,"identifier":"Door"}]}}`,
+			theirs:  `{"externalLevels":true,"nextUid":11,"defs":{"entities":[{"uid":10,"identifier":"Key"}]}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Merge([]byte(tt.base), []byte(tt.ours), []byte(tt.theirs))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Merge() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+
+			if got := string(result.Data); got != tt.want {
+				t.Errorf("Merge() data = %s, want %s", got, tt.want)
+			}
+
+			conflicts := make([]string, 0)
+			for _, c := range result.Conflicts {
+				conflicts = append(conflicts, c.String())
+			}
+			if len(conflicts) > 0 || len(tt.conflicts) > 0 {
+				if !reflect.DeepEqual(conflicts, tt.conflicts) {
+					t.Errorf("Merge() conflicts = %q, want %q", conflicts, tt.conflicts)
+				}
+			}
+
+			if tt.reallocated != nil && !reflect.DeepEqual(result.Reallocated, tt.reallocated) {
+				t.Errorf("Merge() reallocated = %v, want %v", result.Reallocated, tt.reallocated)
+			}
+		})
+	}
+}
+
+func TestMergeIndent(t *testing.T) {
+	tests := []struct {
+		name     string
+		ours     string
+		indented bool
+	}{
+		{name: "minified project", ours: `{"minifyJson":true,"levels":[]}`, indented: false},
+		{name: "indented project", ours: `{"minifyJson":false,"levels":[]}`, indented: true},
+		{name: "indented project minified by hand", ours: "{\n\"minifyJson\": true,\n\"levels\": []\n}", indented: false},
+		{name: "minified level", ours: `{"iid":"a"}`, indented: false},
+		{name: "indented level", ours: "{\n  \"iid\": \"a\"\n}", indented: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Merge([]byte(tt.ours), []byte(tt.ours), []byte(tt.ours))
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+
+			if got := strings.Contains(string(result.Data), "\n"); got != tt.indented {
+				t.Errorf("Merge() indented = %v, want %v:\n%s", got, tt.indented, result.Data)
+			}
+		})
+	}
+}
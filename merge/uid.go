@@ -0,0 +1,201 @@
+package merge
+
+import (
+	"encoding/json"
+	"goldtk/ldtkjson"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// uids lists the objects holding a unique identifier, allocated from
+// `nextUid`. Groups of IntGrid values are numbered within their layer, and
+// are left out.
+func uids(v any, objects map[int64]*ldtkjson.Object) {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			uids(item, objects)
+		}
+	case *ldtkjson.Object:
+		if n, ok := number(v, "uid"); ok {
+			if _, seen := objects[n]; !seen {
+				objects[n] = v
+			}
+		}
+		for _, key := range v.Keys() {
+			if key == "intGridValuesGroups" {
+				continue
+			}
+			child, _ := v.Get(key)
+			uids(child, objects)
+		}
+	}
+}
+
+// isUidKey returns true for the keys holding a unique identifier, or a
+// reference to one.
+func isUidKey(key string) bool {
+	switch key {
+	case "nextUid", "groupUid":
+		return false
+	case "uid", "tilesetId", "levelId":
+		return true
+	}
+
+	return strings.HasSuffix(key, "Uid")
+}
+
+// renumber replaces the unique identifiers and references of v found in
+// remap.
+func renumber(v any, remap map[int64]int64) {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			renumber(item, remap)
+		}
+	case *ldtkjson.Object:
+		for _, key := range v.Keys() {
+			if key == "intGridValuesGroups" {
+				continue
+			}
+			if n, ok := number(v, key); ok && isUidKey(key) {
+				if to, ok := remap[n]; ok {
+					v.Set(key, json.Number(strconv.FormatInt(to, 10)))
+				}
+				continue
+			}
+
+			child, _ := v.Get(key)
+			renumber(child, remap)
+		}
+	}
+}
+
+// reallocate gives new unique identifiers to the objects created by theirs
+// whose unique identifier ours used for something else, and raises
+// `nextUid` of both sides past every unique identifier, so that it merges.
+func reallocate(b, o, t any) map[int64]int64 {
+	remap := make(map[int64]int64)
+
+	bo, ok1 := b.(*ldtkjson.Object)
+	oo, ok2 := o.(*ldtkjson.Object)
+	to, ok3 := t.(*ldtkjson.Object)
+	if !ok1 || !ok2 || !ok3 {
+		return remap
+	}
+	if _, ok := number(oo, "nextUid"); !ok {
+		return remap
+	}
+
+	before := make(map[int64]*ldtkjson.Object)
+	ourObjects := make(map[int64]*ldtkjson.Object)
+	theirObjects := make(map[int64]*ldtkjson.Object)
+	uids(bo, before)
+	uids(oo, ourObjects)
+	uids(to, theirObjects)
+
+	next := int64(0)
+	for _, root := range []*ldtkjson.Object{bo, oo, to} {
+		if n, ok := number(root, "nextUid"); ok {
+			next = max(next, n)
+		}
+	}
+	for _, objects := range []map[int64]*ldtkjson.Object{before, ourObjects, theirObjects} {
+		for uid := range objects {
+			next = max(next, uid+1)
+		}
+	}
+
+	// Reallocated in the order of theirs, so that the result does not depend
+	// on map order.
+	order := make([]int64, 0)
+	var collect func(v any)
+	collect = func(v any) {
+		switch v := v.(type) {
+		case []any:
+			for _, item := range v {
+				collect(item)
+			}
+		case *ldtkjson.Object:
+			if n, ok := number(v, "uid"); ok && theirObjects[n] == v {
+				order = append(order, n)
+			}
+			for _, key := range v.Keys() {
+				if key == "intGridValuesGroups" {
+					continue
+				}
+				child, _ := v.Get(key)
+				collect(child)
+			}
+		}
+	}
+	collect(to)
+
+	for _, uid := range order {
+		if _, ok := before[uid]; ok {
+			continue
+		}
+
+		ours, ok := ourObjects[uid]
+		if !ok || reflect.DeepEqual(ours, theirObjects[uid]) {
+			continue
+		}
+
+		remap[uid] = next
+		next++
+	}
+
+	renumber(to, remap)
+
+	nextUid := json.Number(strconv.FormatInt(next, 10))
+	oo.Set("nextUid", nextUid)
+	to.Set("nextUid", nextUid)
+
+	return remap
+}
+
+// externalLevels returns true for projects saving their levels in separate
+// files.
+func externalLevels(v any) bool {
+	root, ok := v.(*ldtkjson.Object)
+	if !ok {
+		return false
+	}
+
+	external, _ := root.Get("externalLevels")
+	return external == true
+}
+
+// reallocatedUids lists the reallocated unique identifiers, in order.
+func reallocatedUids(remap map[int64]int64) string {
+	sorted := make([]int64, 0, len(remap))
+	for uid := range remap {
+		sorted = append(sorted, uid)
+	}
+	slices.Sort(sorted)
+
+	uids := make([]string, 0, len(sorted))
+	for _, uid := range sorted {
+		uids = append(uids, strconv.FormatInt(uid, 10))
+	}
+
+	return strings.Join(uids, ", ")
+}
+
+// number returns the integer value of key.
+func number(obj *ldtkjson.Object, key string) (int64, bool) {
+	v, ok := obj.Get(key)
+	if !ok {
+		return 0, false
+	}
+
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	i, err := n.Int64()
+	return i, err == nil
+}
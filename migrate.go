@@ -0,0 +1,332 @@
+package goldtk
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"fmt"
+	"goldtk/quicktype"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MigrationKind classifies the changes made by Migrate.
+type MigrationKind string
+
+const (
+	// IidsMigration gives instance identifiers to the project, worlds,
+	// levels, layers and entities of projects saved before 1.0.0. They are
+	// derived from the position of the instances in the project, so that
+	// every load gives the same identifiers.
+	IidsMigration MigrationKind = "Iids"
+
+	// IntGridValuesMigration numbers the IntGrid values of layer definitions
+	// saved before 0.8.0, which were identified by their index.
+	IntGridValuesMigration MigrationKind = "IntGridValues"
+
+	// IntGridCSVMigration converts the legacy `intGrid` cells of a layer to
+	// `intGridCsv`, for layers saved before 1.0.0 or without the CSV format.
+	IntGridCSVMigration MigrationKind = "IntGridCSV"
+
+	// TOCMigration converts the `instances` of a table of contents entry
+	// saved before 1.4.0 to `instancesData`.
+	TOCMigration MigrationKind = "TOC"
+
+	// WorldsMigration shows the levels and world settings of the project
+	// root as a world, for projects saved before 1.0.0 which had no worlds.
+	// The project keeps its levels in the root, as current projects without
+	// the Multi-worlds option do, see Root.Worlds.
+	WorldsMigration MigrationKind = "Worlds"
+)
+
+// Migration describes a change made to a project saved in an older format.
+type Migration struct {
+	Kind MigrationKind
+
+	// Iid is the instance identifier of the migrated world, level or layer,
+	// empty for changes to the whole project.
+	Iid InstanceIdentifier
+
+	Message string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%s: %s", m.Kind, m.Message)
+}
+
+// Migrate normalizes a project saved by an older version of the editor to
+// the current format, and returns the changes made. Load and NewRoot migrate
+// projects on their own, see Root.Migrations.
+//
+// Slices shared with other copies of the project are not modified. Levels
+// stored in external files are migrated when loaded.
+func Migrate(ldtk *quicktype.LdtkJSON) []Migration {
+	migrations := make([]Migration, 0)
+
+	if versionBefore(ldtk.JSONVersion, 0, 8) {
+		migrations = append(migrations, migrateIntGridValues(&ldtk.Defs)...)
+	}
+
+	if ldtk.Iid == "" {
+		ldtk.Iid = projectIid()
+		migrations = append(migrations, Migration{Kind: IidsMigration, Message: "project given an iid"})
+	}
+
+	if len(ldtk.Worlds) == 0 && len(ldtk.Levels) > 0 {
+		if ldtk.DummyWorldIid == "" {
+			ldtk.DummyWorldIid = derivedIid(ldtk.Iid, "world", 0)
+			migrations = append(migrations, Migration{Kind: IidsMigration, Iid: InstanceIdentifier(ldtk.DummyWorldIid), Message: "world of the project levels given an iid"})
+		}
+
+		if versionBefore(ldtk.JSONVersion, 1, 0) {
+			migrations = append(migrations, Migration{
+				Kind:    WorldsMigration,
+				Iid:     InstanceIdentifier(ldtk.DummyWorldIid),
+				Message: fmt.Sprintf("%d levels of the project shown as world %s", len(ldtk.Levels), implicitWorld(*ldtk).Identifier),
+			})
+		}
+	}
+
+	ldtk.Levels = migrateLevels(ldtk.Levels, ldtk.DummyWorldIid, &migrations)
+	ldtk.Worlds = slices.Clone(ldtk.Worlds)
+	for i := range ldtk.Worlds {
+		w := &ldtk.Worlds[i]
+		if w.Iid == "" {
+			w.Iid = derivedIid(ldtk.Iid, "world", i)
+			migrations = append(migrations, Migration{Kind: IidsMigration, Iid: InstanceIdentifier(w.Iid), Message: fmt.Sprintf("world %s given an iid", w.Identifier)})
+		}
+		w.Levels = migrateLevels(w.Levels, w.Iid, &migrations)
+	}
+
+	migrations = append(migrations, migrateTOC(ldtk)...)
+
+	return migrations
+}
+
+// versionBefore returns true when the version, such as "1.5.3", is older
+// than major.minor. Versions which do not parse are not older.
+func versionBefore(version string, major, minor int) bool {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return false
+	}
+
+	ma, err1 := strconv.Atoi(parts[0])
+	mi, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return false
+	}
+
+	return ma < major || (ma == major && mi < minor)
+}
+
+// migrateIntGridValues numbers the IntGrid values of layer definitions from
+// their index, starting at 1 as `intGridCsv` does.
+func migrateIntGridValues(defs *quicktype.Definitions) []Migration {
+	migrations := make([]Migration, 0)
+
+	defs.Layers = slices.Clone(defs.Layers)
+	for i := range defs.Layers {
+		def := &defs.Layers[i]
+		if len(def.IntGridValues) == 0 || slices.ContainsFunc(def.IntGridValues, func(v quicktype.IntGridValueDefinition) bool {
+			return v.Value != 0
+		}) {
+			continue
+		}
+
+		def.IntGridValues = slices.Clone(def.IntGridValues)
+		for j := range def.IntGridValues {
+			def.IntGridValues[j].Value = int64(j + 1)
+		}
+		migrations = append(migrations, Migration{
+			Kind:    IntGridValuesMigration,
+			Message: fmt.Sprintf("%d values of layer definition %s numbered from their index", len(def.IntGridValues), def.Identifier),
+		})
+	}
+
+	return migrations
+}
+
+// migrateLevels migrates the levels of the world worldIid.
+func migrateLevels(levels []quicktype.Level, worldIid string, migrations *[]Migration) []quicktype.Level {
+	levels = slices.Clone(levels)
+	for i := range levels {
+		*migrations = append(*migrations, migrateLevel(&levels[i], worldIid, i)...)
+	}
+
+	return levels
+}
+
+// migrateLevel gives instance identifiers to a level, the level i of the
+// world worldIid, and its content, and converts the legacy `intGrid` cells
+// of its layers to `intGridCsv`.
+func migrateLevel(lvl *quicktype.Level, worldIid string, i int) []Migration {
+	migrations := make([]Migration, 0)
+
+	if lvl.Iid == "" {
+		lvl.Iid = derivedIid(worldIid, "level", i)
+		migrations = append(migrations, Migration{Kind: IidsMigration, Iid: InstanceIdentifier(lvl.Iid), Message: fmt.Sprintf("level %s given an iid", lvl.Identifier)})
+	}
+
+	lvl.LayerInstances = slices.Clone(lvl.LayerInstances)
+	for i := range lvl.LayerInstances {
+		lyr := &lvl.LayerInstances[i]
+
+		if lyr.Iid == "" {
+			lyr.Iid = derivedIid(lvl.Iid, "layer", i)
+			migrations = append(migrations, Migration{
+				Kind:    IidsMigration,
+				Iid:     InstanceIdentifier(lyr.Iid),
+				Message: fmt.Sprintf("layer %s of level %s given an iid", lyr.Identifier, lvl.Identifier),
+			})
+		}
+
+		missing := 0
+		for _, e := range lyr.EntityInstances {
+			if e.Iid == "" {
+				missing++
+			}
+		}
+		if missing > 0 {
+			lyr.EntityInstances = slices.Clone(lyr.EntityInstances)
+			for j := range lyr.EntityInstances {
+				if lyr.EntityInstances[j].Iid == "" {
+					lyr.EntityInstances[j].Iid = derivedIid(lyr.Iid, "entity", j)
+				}
+			}
+			migrations = append(migrations, Migration{
+				Kind:    IidsMigration,
+				Iid:     InstanceIdentifier(lyr.Iid),
+				Message: fmt.Sprintf("%d entities of layer %s of level %s given an iid", missing, lyr.Identifier, lvl.Identifier),
+			})
+		}
+
+		if len(lyr.IntGrid) == 0 || len(lyr.IntGridCSV) > 0 {
+			continue
+		}
+
+		// Legacy values are 0-based, while 0 is an empty cell of the CSV.
+		csv := make([]int64, lyr.CWid*lyr.CHei)
+		for _, cell := range lyr.IntGrid {
+			if cell.CoordID >= 0 && cell.CoordID < int64(len(csv)) {
+				csv[cell.CoordID] = cell.V + 1
+			}
+		}
+
+		migrations = append(migrations, Migration{
+			Kind:    IntGridCSVMigration,
+			Iid:     InstanceIdentifier(lyr.Iid),
+			Message: fmt.Sprintf("%d intGrid cells of layer %s of level %s converted to intGridCsv", len(lyr.IntGrid), lyr.Identifier, lvl.Identifier),
+		})
+		lyr.IntGridCSV = csv
+		lyr.IntGrid = nil
+	}
+
+	return migrations
+}
+
+// migrateTOC converts the references of legacy table of contents entries to
+// instance data. Positions and fields are read from the entities stored in
+// the project file, and left empty for entities of external levels.
+func migrateTOC(ldtk *quicktype.LdtkJSON) []Migration {
+	migrations := make([]Migration, 0)
+
+	needed := slices.ContainsFunc(ldtk.Toc, func(e quicktype.LdtkTableOfContentEntry) bool {
+		return len(e.Instances) > 0 && len(e.InstancesData) == 0
+	})
+	if !needed {
+		return migrations
+	}
+
+	toc := make(map[int64]bool)
+	for _, def := range ldtk.Defs.Entities {
+		for _, f := range def.FieldDefs {
+			if f.ExportToToc {
+				toc[f.Uid] = true
+			}
+		}
+	}
+
+	entities := make(map[string]quicktype.EntityInstance)
+	levels := slices.Clone(ldtk.Levels)
+	for _, w := range ldtk.Worlds {
+		levels = append(levels, w.Levels...)
+	}
+	for _, lvl := range levels {
+		for _, lyr := range lvl.LayerInstances {
+			for _, e := range lyr.EntityInstances {
+				entities[e.Iid] = e
+			}
+		}
+	}
+
+	ldtk.Toc = slices.Clone(ldtk.Toc)
+	for i := range ldtk.Toc {
+		entry := &ldtk.Toc[i]
+		if len(entry.Instances) == 0 || len(entry.InstancesData) > 0 {
+			continue
+		}
+
+		located := 0
+		entry.InstancesData = make([]quicktype.LdtkTocInstanceData, 0, len(entry.Instances))
+		for _, ref := range entry.Instances {
+			data := quicktype.LdtkTocInstanceData{Iids: ref, Fields: map[string]any{}}
+
+			if e, ok := entities[ref.EntityIid]; ok {
+				located++
+				data.WidPx, data.HeiPx = e.Width, e.Height
+				if e.WorldX != nil && e.WorldY != nil {
+					data.WorldX, data.WorldY = *e.WorldX, *e.WorldY
+				}
+
+				fields := make(map[string]any)
+				for _, f := range e.FieldInstances {
+					if toc[f.DefUid] {
+						fields[f.Identifier] = f.Value
+					}
+				}
+				data.Fields = fields
+			}
+
+			entry.InstancesData = append(entry.InstancesData, data)
+		}
+
+		migrations = append(migrations, Migration{
+			Kind: TOCMigration,
+			Message: fmt.Sprintf("%d instances of table of contents entry %s converted to instancesData, %d with their position and fields",
+				len(entry.Instances), entry.Identifier, located),
+		})
+		entry.Instances = nil
+	}
+
+	return migrations
+}
+
+// projectIid is the instance identifier given to projects without one. It is
+// the same for every project, as nothing else identifies a project saved
+// before 1.0.0.
+func projectIid() string {
+	return derivedIid("", "project", 0)
+}
+
+// derivedIid returns the instance identifier of the instance i of the given
+// kind in the instance parent, a version 5 UUID.
+func derivedIid(parent, kind string, i int) string {
+	sum := sha1.Sum([]byte(parent + "/" + kind + "/" + strconv.Itoa(i)))
+	b := sum[:16]
+	b[6] = b[6]&0x0f | 0x50
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// NewIid returns a random instance identifier, a version 4 UUID like the ones
+// of the editor.
+func NewIid() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
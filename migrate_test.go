@@ -0,0 +1,119 @@
+package goldtk
+
+import (
+	"goldtk/quicktype"
+	"reflect"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name string
+		ldtk string
+		want []MigrationKind
+	}{
+		{
+			name: "current project",
+			ldtk: `{"jsonVersion":"1.5.3","iid":"p","dummyWorldIid":"w","levels":[{"iid":"l","layerInstances":[{"iid":"y","entityInstances":[{"iid":"e"}]}]}],"worlds":[]}`,
+			want: []MigrationKind{},
+		},
+		{
+			name: "current project with worlds",
+			ldtk: `{"jsonVersion":"1.5.3","iid":"p","levels":[],"worlds":[{"iid":"w","levels":[{"iid":"l"}]}]}`,
+			want: []MigrationKind{},
+		},
+		{
+			name: "project without iids",
+			ldtk: `{"jsonVersion":"0.9.3","levels":[{"identifier":"A","layerInstances":[{"identifier":"L","entityInstances":[{},{"iid":"e"}]}]}]}`,
+			want: []MigrationKind{IidsMigration, IidsMigration, WorldsMigration, IidsMigration, IidsMigration, IidsMigration},
+		},
+		{
+			name: "worlds without iids",
+			ldtk: `{"jsonVersion":"1.5.3","iid":"p","worlds":[{"identifier":"W","levels":[{"iid":"l"}]}]}`,
+			want: []MigrationKind{IidsMigration},
+		},
+		{
+			name: "IntGrid values",
+			ldtk: `{"jsonVersion":"0.7.2","iid":"p","defs":{"layers":[{"identifier":"L","intGridValues":[{"value":0},{"value":0}]}]}}`,
+			want: []MigrationKind{IntGridValuesMigration},
+		},
+		{
+			name: "IntGrid cells",
+			ldtk: `{"jsonVersion":"1.5.3","iid":"p","dummyWorldIid":"w","levels":[{"iid":"l","layerInstances":[{"iid":"y","__cWid":2,"__cHei":1,"intGrid":[{"coordId":1,"v":0}]}]}]}`,
+			want: []MigrationKind{IntGridCSVMigration},
+		},
+		{
+			name: "table of contents",
+			ldtk: `{"jsonVersion":"1.3.0","iid":"p","toc":[{"identifier":"Door","instances":[{"entityIid":"e","layerIid":"y","levelIid":"l","worldIid":"w"}]}]}`,
+			want: []MigrationKind{TOCMigration},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ldtk, err := quicktype.UnmarshalLdtkJSON([]byte(tt.ldtk))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			kinds := make([]MigrationKind, 0)
+			for _, m := range Migrate(&ldtk) {
+				kinds = append(kinds, m.Kind)
+			}
+			if !reflect.DeepEqual(kinds, tt.want) {
+				t.Errorf("Migrate() kinds = %v, want %v", kinds, tt.want)
+			}
+
+			// The root levels of old projects are shown as a world on every
+			// load, the project itself is not changed.
+			for _, m := range Migrate(&ldtk) {
+				if m.Kind != WorldsMigration {
+					t.Errorf("Migrate() of a migrated project = %v, want none", m)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrateIids(t *testing.T) {
+	data := []byte(`{"jsonVersion":"0.9.3","levels":[{"identifier":"A","layerInstances":[{"identifier":"L","entityInstances":[{},{}]}]}]}`)
+
+	iids := func() []string {
+		ldtk, err := quicktype.UnmarshalLdtkJSON(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		Migrate(&ldtk)
+
+		lvl := ldtk.Levels[0]
+		lyr := lvl.LayerInstances[0]
+		return []string{ldtk.Iid, ldtk.DummyWorldIid, lvl.Iid, lyr.Iid, lyr.EntityInstances[0].Iid, lyr.EntityInstances[1].Iid}
+	}
+
+	first, second := iids(), iids()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Migrate() iids = %v, then %v", first, second)
+	}
+
+	seen := make(map[string]bool)
+	for _, iid := range first {
+		if iid == "" || seen[iid] {
+			t.Errorf("Migrate() iids = %v, want distinct iids", first)
+			break
+		}
+		seen[iid] = true
+	}
+}
+
+func TestMigrateIntGridCells(t *testing.T) {
+	ldtk, err := quicktype.UnmarshalLdtkJSON([]byte(`{"jsonVersion":"0.9.3","iid":"p","dummyWorldIid":"w","levels":[{"iid":"l","layerInstances":[{"iid":"y","__cWid":3,"__cHei":1,"intGrid":[{"coordId":0,"v":1},{"coordId":2,"v":0}]}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Migrate(&ldtk)
+
+	lyr := ldtk.Levels[0].LayerInstances[0]
+	if want := []int64{2, 0, 1}; !reflect.DeepEqual(lyr.IntGridCSV, want) || lyr.IntGrid != nil {
+		t.Errorf("Migrate() intGridCsv = %v, intGrid = %v, want %v and none", lyr.IntGridCSV, lyr.IntGrid, want)
+	}
+}
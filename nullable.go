@@ -51,7 +51,7 @@ func (n nullable[T]) Get() (T, bool) {
 		v = *n.v
 	}
 
-	return v, n.v == nil
+	return v, n.v != nil
 }
 
 var _ Nullable[struct{}] = nullable[struct{}]{}
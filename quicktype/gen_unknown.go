@@ -0,0 +1,139 @@
+//go:build ignore
+
+// gen_unknown adds an Unknown field to every struct of quicktype.go, and
+// writes unknown_gen.go with the JSON methods filling it and writing it back.
+// It runs after quicktype.go is downloaded again, see `task quicktype`.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const source = "quicktype.go"
+
+const unknownField = "\n\t// Unknown holds the keys this version of the package does not know, so\n" +
+	"\t// that they are written back by MarshalJSON.\n" +
+	"\tUnknown map[string]json.RawMessage `json:\"-\"`\n"
+
+type structType struct {
+	name string
+	keys []string
+}
+
+func main() {
+	src, err := os.ReadFile(source)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, source, src, parser.ParseComments)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	structs := make([]structType, 0)
+	inserts := make([]int, 0)
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+
+		s := structType{name: spec.Name.Name, keys: make([]string, 0)}
+		hasUnknown := false
+		for _, f := range st.Fields.List {
+			for _, name := range f.Names {
+				if name.Name == "Unknown" {
+					hasUnknown = true
+				}
+			}
+			if f.Tag == nil {
+				continue
+			}
+
+			tag, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				log.Fatal(err)
+			}
+			key, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+			if key != "" && key != "-" {
+				s.keys = append(s.keys, key)
+			}
+		}
+
+		structs = append(structs, s)
+		if !hasUnknown {
+			inserts = append(inserts, fset.Position(st.Fields.Closing).Offset)
+		}
+
+		return false
+	})
+
+	slices.Reverse(inserts)
+	for _, offset := range inserts {
+		src = slices.Concat(src[:offset:offset], []byte(unknownField), src[offset:])
+	}
+	write(source, src)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_unknown.go; DO NOT EDIT.\n\npackage quicktype\n\nimport \"encoding/json\"\n")
+	for _, s := range structs {
+		lower := strings.ToLower(s.name[:1]) + s.name[1:]
+		fmt.Fprintf(&buf, `
+var %[2]sKeys = map[string]bool{%[3]s}
+
+func (x *%[1]s) UnmarshalJSON(data []byte) error {
+	type plain %[1]s
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, %[2]sKeys)
+
+	return nil
+}
+
+func (x %[1]s) MarshalJSON() ([]byte, error) {
+	type plain %[1]s
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+`, s.name, lower, quoteKeys(s.keys))
+	}
+	write("unknown_gen.go", buf.Bytes())
+}
+
+func quoteKeys(keys []string) string {
+	quoted := make([]string, 0, len(keys))
+	for _, k := range keys {
+		quoted = append(quoted, strconv.Quote(k)+": true")
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func write(name string, src []byte) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Fatalf("formatting %s: %v", name, err)
+	}
+
+	if err := os.WriteFile(name, formatted, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
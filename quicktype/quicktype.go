@@ -140,12 +140,20 @@ type LdtkJSON struct {
 	// supporting this future update easily, please refer to this documentation:
 	// https://github.com/deepnight/ldtk/issues/231
 	Worlds []World `json:"worlds"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 type LdtkCustomCommand struct {
 	Command string `json:"command"`
 	// Possible values: `Manual`, `AfterLoad`, `BeforeSave`, `AfterSave`
 	When When `json:"when"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // If you're writing your own LDtk importer, you should probably just ignore *most* stuff in
@@ -170,6 +178,10 @@ type Definitions struct {
 	LevelFields []FieldDefinition `json:"levelFields"`
 	// All tilesets
 	Tilesets []TilesetDefinition `json:"tilesets"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 type EntityDefinition struct {
@@ -245,6 +257,10 @@ type EntityDefinition struct {
 	UITileRect *TilesetRectangle `json:"uiTileRect,omitempty"`
 	// Pixel width
 	Width int64 `json:"width"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // This section is mostly only intended for the LDtk editor app itself. You can safely
@@ -324,6 +340,10 @@ type FieldDefinition struct {
 	// color in the editor UI. For Enum fields, this would be the color associated to their
 	// values.
 	UseForSmartColor bool `json:"useForSmartColor"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // This object represents a custom sub rectangle in a Tileset image.
@@ -338,6 +358,10 @@ type TilesetRectangle struct {
 	X int64 `json:"x"`
 	// Y pixels coordinate of the top-left corner in the Tileset image
 	Y int64 `json:"y"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 type EnumDefinition struct {
@@ -354,6 +378,10 @@ type EnumDefinition struct {
 	Uid int64 `json:"uid"`
 	// All possible enum values, with their optional Tile infos.
 	Values []EnumValueDefinition `json:"values"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 type EnumValueDefinition struct {
@@ -369,6 +397,10 @@ type EnumValueDefinition struct {
 	TileID *int64 `json:"tileId,omitempty"`
 	// Optional tileset rectangle to represents this value
 	TileRect *TilesetRectangle `json:"tileRect,omitempty"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 type LayerDefinition struct {
@@ -450,6 +482,10 @@ type LayerDefinition struct {
 	UIFilterTags []string `json:"uiFilterTags"`
 	// Asynchronous rendering option for large/complex layers
 	UseAsyncRender bool `json:"useAsyncRender"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 type AutoLayerRuleGroup struct {
@@ -465,6 +501,10 @@ type AutoLayerRuleGroup struct {
 	Rules               []AutoLayerRuleDefinition `json:"rules"`
 	Uid                 int64                     `json:"uid"`
 	UsesWizard          bool                      `json:"usesWizard"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // This complex section isn't meant to be used by game devs at all, as these rules are
@@ -531,6 +571,10 @@ type AutoLayerRuleDefinition struct {
 	YModulo int64 `json:"yModulo"`
 	// Y cell start offset
 	YOffset int64 `json:"yOffset"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // IntGrid value definition
@@ -543,6 +587,10 @@ type IntGridValueDefinition struct {
 	Tile       *TilesetRectangle `json:"tile,omitempty"`
 	// The IntGrid value itself
 	Value int64 `json:"value"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // IntGrid value group definition
@@ -553,6 +601,10 @@ type IntGridValueGroupDefinition struct {
 	Identifier *string `json:"identifier,omitempty"`
 	// Group unique ID
 	Uid int64 `json:"uid"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // The `Tileset` definition is the most important part among project definitions. It
@@ -596,18 +648,30 @@ type TilesetDefinition struct {
 	TileGridSize      int64  `json:"tileGridSize"`
 	// Unique Intidentifier
 	Uid int64 `json:"uid"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // In a tileset definition, user defined meta-data of a tile.
 type TileCustomMetadata struct {
 	Data   string `json:"data"`
 	TileID int64  `json:"tileId"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // In a tileset definition, enum based tag infos
 type EnumTagValue struct {
 	EnumValueID string  `json:"enumValueId"`
 	TileIDS     []int64 `json:"tileIds"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // This object is not actually used by LDtk. It ONLY exists to force explicit references to
@@ -642,6 +706,10 @@ type ForcedRefs struct {
 	TilesetRect          *TilesetRectangle            `json:"TilesetRect,omitempty"`
 	TocInstanceData      *LdtkTocInstanceData         `json:"TocInstanceData,omitempty"`
 	World                *World                       `json:"World,omitempty"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 type EntityInstance struct {
@@ -678,6 +746,10 @@ type EntityInstance struct {
 	// Entity width in pixels. For non-resizable entities, it will be the same as Entity
 	// definition.
 	Width int64 `json:"width"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 type FieldInstance struct {
@@ -704,6 +776,10 @@ type FieldInstance struct {
 	DefUid int64 `json:"defUid"`
 	// Editor internal raw values
 	RealEditorValues []interface{} `json:"realEditorValues"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // This object describes the "location" of an Entity instance in the project worlds.
@@ -718,6 +794,10 @@ type ReferenceToAnEntityInstance struct {
 	LevelIid string `json:"levelIid"`
 	// IID of the World containing the refered EntityInstance
 	WorldIid string `json:"worldIid"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // This object is just a grid-based coordinate used in Field values.
@@ -726,6 +806,10 @@ type GridPoint struct {
 	Cx int64 `json:"cx"`
 	// Y grid-based coordinate
 	Cy int64 `json:"cy"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // IntGrid value instance
@@ -734,6 +818,10 @@ type IntGridValueInstance struct {
 	CoordID int64 `json:"coordId"`
 	// IntGrid value
 	V int64 `json:"v"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 type LayerInstance struct {
@@ -795,6 +883,10 @@ type LayerInstance struct {
 	Seed int64 `json:"seed"`
 	// Layer instance visibility
 	Visible bool `json:"visible"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // This structure represents a single tile from a given Tileset.
@@ -815,6 +907,10 @@ type TileInstance struct {
 	Src []int64 `json:"src"`
 	// The *Tile ID* in the corresponding tileset.
 	T int64 `json:"t"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // This section contains all the level data. It can be found in 2 distinct forms, depending
@@ -887,6 +983,10 @@ type Level struct {
 	// positioning is manual (ie. GridVania, Free). For Horizontal and Vertical layouts, the
 	// value is always -1 here.
 	WorldY int64 `json:"worldY"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // Level background image position info
@@ -901,6 +1001,10 @@ type LevelBackgroundPosition struct {
 	// An array containing the `[x,y]` pixel coordinates of the top-left corner of the
 	// **cropped** background image, depending on `bgPos` option.
 	TopLeftPx []int64 `json:"topLeftPx"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // Nearby level info
@@ -916,6 +1020,10 @@ type NeighbourLevel struct {
 	// **WARNING**: this deprecated value is no longer exported since version 1.2.0  Replaced
 	// by: `levelIid`
 	LevelUid *int64 `json:"levelUid,omitempty"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 type LdtkTableOfContentEntry struct {
@@ -924,6 +1032,10 @@ type LdtkTableOfContentEntry struct {
 	// Replaced by: `instancesData`
 	Instances     []ReferenceToAnEntityInstance `json:"instances,omitempty"`
 	InstancesData []LdtkTocInstanceData         `json:"instancesData"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 type LdtkTocInstanceData struct {
@@ -936,6 +1048,10 @@ type LdtkTocInstanceData struct {
 	WidPx  int64                       `json:"widPx"`
 	WorldX int64                       `json:"worldX"`
 	WorldY int64                       `json:"worldY"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // **IMPORTANT**: this type is available as a preview. You can rely on it to update your
@@ -961,6 +1077,10 @@ type World struct {
 	// An enum that describes how levels are organized in this project (ie. linearly or in a 2D
 	// space). Possible values: `Free`, `GridVania`, `LinearHorizontal`, `LinearVertical`, `null`
 	WorldLayout *WorldLayout `json:"worldLayout"`
+
+	// Unknown holds the keys this version of the package does not know, so
+	// that they are written back by MarshalJSON.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
 // Possible values: `Manual`, `AfterLoad`, `BeforeSave`, `AfterSave`
@@ -0,0 +1,139 @@
+package quicktype
+
+//go:generate go run gen_unknown.go
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+)
+
+// unknownKeys returns the members of the JSON object data whose key is not
+// known, or nil when there are none. data must be valid JSON: it is the object
+// json.Unmarshal has just decoded, so the members are found by skipping over
+// their values rather than by decoding the object a second time.
+func unknownKeys(data []byte, known map[string]bool) map[string]json.RawMessage {
+	var unknown map[string]json.RawMessage
+	i := skipSpace(data, 0)
+	if i == len(data) || data[i] != '{' {
+		return nil
+	}
+	for i = skipSpace(data, i+1); i < len(data) && data[i] == '"'; {
+		end := skipString(data, i)
+		key := data[i+1 : end-1]
+		i = skipSpace(data, skipSpace(data, end)+1)
+		end = skipValue(data, i)
+		if name := unquoteKey(key); !known[name] {
+			if unknown == nil {
+				unknown = make(map[string]json.RawMessage)
+			}
+			unknown[name] = bytes.Clone(data[i:end])
+		}
+
+		i = skipSpace(data, end)
+		if i < len(data) && data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+
+	return unknown
+}
+
+// unquoteKey returns the key between the quotes of a JSON string.
+func unquoteKey(key []byte) string {
+	if bytes.IndexByte(key, '\\') < 0 {
+		return string(key)
+	}
+
+	var s string
+	if err := json.Unmarshal(slices.Concat([]byte{'"'}, key, []byte{'"'}), &s); err != nil {
+		return string(key)
+	}
+
+	return s
+}
+
+// skipSpace returns the index of the first byte of data at or after i that is
+// not white space.
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+
+	return i
+}
+
+// skipString returns the index following the JSON string starting at i.
+func skipString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+
+	return i
+}
+
+// skipValue returns the index following the JSON value starting at i.
+func skipValue(data []byte, i int) int {
+	depth := 0
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			i = skipString(data, i) - 1
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ',', ' ', '\t', '\n', '\r':
+			if depth == 0 {
+				return i
+			}
+		}
+		if depth == 0 && (data[i] == '"' || data[i] == '}' || data[i] == ']') {
+			return i + 1
+		}
+	}
+
+	return i
+}
+
+// marshalWithUnknown marshals the struct v, followed by the unknown members
+// in key order.
+func marshalWithUnknown(v any, unknown map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(unknown) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(unknown))
+	for key := range unknown {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for i, key := range keys {
+		if i > 0 || len(data) > 2 {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(unknown[key])
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,545 @@
+// Code generated by gen_unknown.go; DO NOT EDIT.
+
+package quicktype
+
+import "encoding/json"
+
+var ldtkJSONKeys = map[string]bool{"__FORCED_REFS": true, "appBuildId": true, "backupLimit": true, "backupOnSave": true, "backupRelPath": true, "bgColor": true, "customCommands": true, "defaultEntityHeight": true, "defaultEntityWidth": true, "defaultGridSize": true, "defaultLevelBgColor": true, "defaultLevelHeight": true, "defaultLevelWidth": true, "defaultPivotX": true, "defaultPivotY": true, "defs": true, "dummyWorldIid": true, "exportLevelBg": true, "exportPng": true, "exportTiled": true, "externalLevels": true, "flags": true, "identifierStyle": true, "iid": true, "imageExportMode": true, "jsonVersion": true, "levelNamePattern": true, "levels": true, "minifyJson": true, "nextUid": true, "pngFilePattern": true, "simplifiedExport": true, "toc": true, "tutorialDesc": true, "worldGridHeight": true, "worldGridWidth": true, "worldLayout": true, "worlds": true}
+
+func (x *LdtkJSON) UnmarshalJSON(data []byte) error {
+	type plain LdtkJSON
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, ldtkJSONKeys)
+
+	return nil
+}
+
+func (x LdtkJSON) MarshalJSON() ([]byte, error) {
+	type plain LdtkJSON
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var ldtkCustomCommandKeys = map[string]bool{"command": true, "when": true}
+
+func (x *LdtkCustomCommand) UnmarshalJSON(data []byte) error {
+	type plain LdtkCustomCommand
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, ldtkCustomCommandKeys)
+
+	return nil
+}
+
+func (x LdtkCustomCommand) MarshalJSON() ([]byte, error) {
+	type plain LdtkCustomCommand
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var definitionsKeys = map[string]bool{"entities": true, "enums": true, "externalEnums": true, "layers": true, "levelFields": true, "tilesets": true}
+
+func (x *Definitions) UnmarshalJSON(data []byte) error {
+	type plain Definitions
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, definitionsKeys)
+
+	return nil
+}
+
+func (x Definitions) MarshalJSON() ([]byte, error) {
+	type plain Definitions
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var entityDefinitionKeys = map[string]bool{"allowOutOfBounds": true, "color": true, "doc": true, "exportToToc": true, "fieldDefs": true, "fillOpacity": true, "height": true, "hollow": true, "identifier": true, "keepAspectRatio": true, "limitBehavior": true, "limitScope": true, "lineOpacity": true, "maxCount": true, "maxHeight": true, "maxWidth": true, "minHeight": true, "minWidth": true, "nineSliceBorders": true, "pivotX": true, "pivotY": true, "renderMode": true, "resizableX": true, "resizableY": true, "showName": true, "tags": true, "tileId": true, "tileOpacity": true, "tileRect": true, "tileRenderMode": true, "tilesetId": true, "uid": true, "uiTileRect": true, "width": true}
+
+func (x *EntityDefinition) UnmarshalJSON(data []byte) error {
+	type plain EntityDefinition
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, entityDefinitionKeys)
+
+	return nil
+}
+
+func (x EntityDefinition) MarshalJSON() ([]byte, error) {
+	type plain EntityDefinition
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var fieldDefinitionKeys = map[string]bool{"__type": true, "acceptFileTypes": true, "allowedRefs": true, "allowedRefsEntityUid": true, "allowedRefTags": true, "allowOutOfLevelRef": true, "arrayMaxLength": true, "arrayMinLength": true, "autoChainRef": true, "canBeNull": true, "defaultOverride": true, "doc": true, "editorAlwaysShow": true, "editorCutLongValues": true, "editorDisplayColor": true, "editorDisplayMode": true, "editorDisplayPos": true, "editorDisplayScale": true, "editorLinkStyle": true, "editorShowInWorld": true, "editorTextPrefix": true, "editorTextSuffix": true, "exportToToc": true, "identifier": true, "isArray": true, "max": true, "min": true, "regex": true, "searchable": true, "symmetricalRef": true, "textLanguageMode": true, "tilesetUid": true, "type": true, "uid": true, "useForSmartColor": true}
+
+func (x *FieldDefinition) UnmarshalJSON(data []byte) error {
+	type plain FieldDefinition
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, fieldDefinitionKeys)
+
+	return nil
+}
+
+func (x FieldDefinition) MarshalJSON() ([]byte, error) {
+	type plain FieldDefinition
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var tilesetRectangleKeys = map[string]bool{"h": true, "tilesetUid": true, "w": true, "x": true, "y": true}
+
+func (x *TilesetRectangle) UnmarshalJSON(data []byte) error {
+	type plain TilesetRectangle
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, tilesetRectangleKeys)
+
+	return nil
+}
+
+func (x TilesetRectangle) MarshalJSON() ([]byte, error) {
+	type plain TilesetRectangle
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var enumDefinitionKeys = map[string]bool{"externalFileChecksum": true, "externalRelPath": true, "iconTilesetUid": true, "identifier": true, "tags": true, "uid": true, "values": true}
+
+func (x *EnumDefinition) UnmarshalJSON(data []byte) error {
+	type plain EnumDefinition
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, enumDefinitionKeys)
+
+	return nil
+}
+
+func (x EnumDefinition) MarshalJSON() ([]byte, error) {
+	type plain EnumDefinition
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var enumValueDefinitionKeys = map[string]bool{"__tileSrcRect": true, "color": true, "id": true, "tileId": true, "tileRect": true}
+
+func (x *EnumValueDefinition) UnmarshalJSON(data []byte) error {
+	type plain EnumValueDefinition
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, enumValueDefinitionKeys)
+
+	return nil
+}
+
+func (x EnumValueDefinition) MarshalJSON() ([]byte, error) {
+	type plain EnumValueDefinition
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var layerDefinitionKeys = map[string]bool{"__type": true, "autoRuleGroups": true, "autoSourceLayerDefUid": true, "autoTilesetDefUid": true, "autoTilesKilledByOtherLayerUid": true, "biomeFieldUid": true, "canSelectWhenInactive": true, "displayOpacity": true, "doc": true, "excludedTags": true, "gridSize": true, "guideGridHei": true, "guideGridWid": true, "hideFieldsWhenInactive": true, "hideInList": true, "identifier": true, "inactiveOpacity": true, "intGridValues": true, "intGridValuesGroups": true, "parallaxFactorX": true, "parallaxFactorY": true, "parallaxScaling": true, "pxOffsetX": true, "pxOffsetY": true, "renderInWorldView": true, "requiredTags": true, "tilePivotX": true, "tilePivotY": true, "tilesetDefUid": true, "type": true, "uiColor": true, "uid": true, "uiFilterTags": true, "useAsyncRender": true}
+
+func (x *LayerDefinition) UnmarshalJSON(data []byte) error {
+	type plain LayerDefinition
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, layerDefinitionKeys)
+
+	return nil
+}
+
+func (x LayerDefinition) MarshalJSON() ([]byte, error) {
+	type plain LayerDefinition
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var autoLayerRuleGroupKeys = map[string]bool{"active": true, "biomeRequirementMode": true, "collapsed": true, "color": true, "icon": true, "isOptional": true, "name": true, "requiredBiomeValues": true, "rules": true, "uid": true, "usesWizard": true}
+
+func (x *AutoLayerRuleGroup) UnmarshalJSON(data []byte) error {
+	type plain AutoLayerRuleGroup
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, autoLayerRuleGroupKeys)
+
+	return nil
+}
+
+func (x AutoLayerRuleGroup) MarshalJSON() ([]byte, error) {
+	type plain AutoLayerRuleGroup
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var autoLayerRuleDefinitionKeys = map[string]bool{"active": true, "alpha": true, "breakOnMatch": true, "chance": true, "checker": true, "flipX": true, "flipY": true, "invalidated": true, "outOfBoundsValue": true, "pattern": true, "perlinActive": true, "perlinOctaves": true, "perlinScale": true, "perlinSeed": true, "pivotX": true, "pivotY": true, "size": true, "tileIds": true, "tileMode": true, "tileRandomXMax": true, "tileRandomXMin": true, "tileRandomYMax": true, "tileRandomYMin": true, "tileRectsIds": true, "tileXOffset": true, "tileYOffset": true, "uid": true, "xModulo": true, "xOffset": true, "yModulo": true, "yOffset": true}
+
+func (x *AutoLayerRuleDefinition) UnmarshalJSON(data []byte) error {
+	type plain AutoLayerRuleDefinition
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, autoLayerRuleDefinitionKeys)
+
+	return nil
+}
+
+func (x AutoLayerRuleDefinition) MarshalJSON() ([]byte, error) {
+	type plain AutoLayerRuleDefinition
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var intGridValueDefinitionKeys = map[string]bool{"color": true, "groupUid": true, "identifier": true, "tile": true, "value": true}
+
+func (x *IntGridValueDefinition) UnmarshalJSON(data []byte) error {
+	type plain IntGridValueDefinition
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, intGridValueDefinitionKeys)
+
+	return nil
+}
+
+func (x IntGridValueDefinition) MarshalJSON() ([]byte, error) {
+	type plain IntGridValueDefinition
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var intGridValueGroupDefinitionKeys = map[string]bool{"color": true, "identifier": true, "uid": true}
+
+func (x *IntGridValueGroupDefinition) UnmarshalJSON(data []byte) error {
+	type plain IntGridValueGroupDefinition
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, intGridValueGroupDefinitionKeys)
+
+	return nil
+}
+
+func (x IntGridValueGroupDefinition) MarshalJSON() ([]byte, error) {
+	type plain IntGridValueGroupDefinition
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var tilesetDefinitionKeys = map[string]bool{"__cHei": true, "__cWid": true, "cachedPixelData": true, "customData": true, "embedAtlas": true, "enumTags": true, "identifier": true, "padding": true, "pxHei": true, "pxWid": true, "relPath": true, "savedSelections": true, "spacing": true, "tags": true, "tagsSourceEnumUid": true, "tileGridSize": true, "uid": true}
+
+func (x *TilesetDefinition) UnmarshalJSON(data []byte) error {
+	type plain TilesetDefinition
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, tilesetDefinitionKeys)
+
+	return nil
+}
+
+func (x TilesetDefinition) MarshalJSON() ([]byte, error) {
+	type plain TilesetDefinition
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var tileCustomMetadataKeys = map[string]bool{"data": true, "tileId": true}
+
+func (x *TileCustomMetadata) UnmarshalJSON(data []byte) error {
+	type plain TileCustomMetadata
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, tileCustomMetadataKeys)
+
+	return nil
+}
+
+func (x TileCustomMetadata) MarshalJSON() ([]byte, error) {
+	type plain TileCustomMetadata
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var enumTagValueKeys = map[string]bool{"enumValueId": true, "tileIds": true}
+
+func (x *EnumTagValue) UnmarshalJSON(data []byte) error {
+	type plain EnumTagValue
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, enumTagValueKeys)
+
+	return nil
+}
+
+func (x EnumTagValue) MarshalJSON() ([]byte, error) {
+	type plain EnumTagValue
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var forcedRefsKeys = map[string]bool{"AutoLayerRuleGroup": true, "AutoRuleDef": true, "CustomCommand": true, "Definitions": true, "EntityDef": true, "EntityInstance": true, "EntityReferenceInfos": true, "EnumDef": true, "EnumDefValues": true, "EnumTagValue": true, "FieldDef": true, "FieldInstance": true, "GridPoint": true, "IntGridValueDef": true, "IntGridValueGroupDef": true, "IntGridValueInstance": true, "LayerDef": true, "LayerInstance": true, "Level": true, "LevelBgPosInfos": true, "NeighbourLevel": true, "TableOfContentEntry": true, "Tile": true, "TileCustomMetadata": true, "TilesetDef": true, "TilesetRect": true, "TocInstanceData": true, "World": true}
+
+func (x *ForcedRefs) UnmarshalJSON(data []byte) error {
+	type plain ForcedRefs
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, forcedRefsKeys)
+
+	return nil
+}
+
+func (x ForcedRefs) MarshalJSON() ([]byte, error) {
+	type plain ForcedRefs
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var entityInstanceKeys = map[string]bool{"__grid": true, "__identifier": true, "__pivot": true, "__smartColor": true, "__tags": true, "__tile": true, "__worldX": true, "__worldY": true, "defUid": true, "fieldInstances": true, "height": true, "iid": true, "px": true, "width": true}
+
+func (x *EntityInstance) UnmarshalJSON(data []byte) error {
+	type plain EntityInstance
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, entityInstanceKeys)
+
+	return nil
+}
+
+func (x EntityInstance) MarshalJSON() ([]byte, error) {
+	type plain EntityInstance
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var fieldInstanceKeys = map[string]bool{"__identifier": true, "__tile": true, "__type": true, "__value": true, "defUid": true, "realEditorValues": true}
+
+func (x *FieldInstance) UnmarshalJSON(data []byte) error {
+	type plain FieldInstance
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, fieldInstanceKeys)
+
+	return nil
+}
+
+func (x FieldInstance) MarshalJSON() ([]byte, error) {
+	type plain FieldInstance
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var referenceToAnEntityInstanceKeys = map[string]bool{"entityIid": true, "layerIid": true, "levelIid": true, "worldIid": true}
+
+func (x *ReferenceToAnEntityInstance) UnmarshalJSON(data []byte) error {
+	type plain ReferenceToAnEntityInstance
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, referenceToAnEntityInstanceKeys)
+
+	return nil
+}
+
+func (x ReferenceToAnEntityInstance) MarshalJSON() ([]byte, error) {
+	type plain ReferenceToAnEntityInstance
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var gridPointKeys = map[string]bool{"cx": true, "cy": true}
+
+func (x *GridPoint) UnmarshalJSON(data []byte) error {
+	type plain GridPoint
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, gridPointKeys)
+
+	return nil
+}
+
+func (x GridPoint) MarshalJSON() ([]byte, error) {
+	type plain GridPoint
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var intGridValueInstanceKeys = map[string]bool{"coordId": true, "v": true}
+
+func (x *IntGridValueInstance) UnmarshalJSON(data []byte) error {
+	type plain IntGridValueInstance
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, intGridValueInstanceKeys)
+
+	return nil
+}
+
+func (x IntGridValueInstance) MarshalJSON() ([]byte, error) {
+	type plain IntGridValueInstance
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var layerInstanceKeys = map[string]bool{"__cHei": true, "__cWid": true, "__gridSize": true, "__identifier": true, "__opacity": true, "__pxTotalOffsetX": true, "__pxTotalOffsetY": true, "__tilesetDefUid": true, "__tilesetRelPath": true, "__type": true, "autoLayerTiles": true, "entityInstances": true, "gridTiles": true, "iid": true, "intGrid": true, "intGridCsv": true, "layerDefUid": true, "levelId": true, "optionalRules": true, "overrideTilesetUid": true, "pxOffsetX": true, "pxOffsetY": true, "seed": true, "visible": true}
+
+func (x *LayerInstance) UnmarshalJSON(data []byte) error {
+	type plain LayerInstance
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, layerInstanceKeys)
+
+	return nil
+}
+
+func (x LayerInstance) MarshalJSON() ([]byte, error) {
+	type plain LayerInstance
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var tileInstanceKeys = map[string]bool{"a": true, "d": true, "f": true, "px": true, "src": true, "t": true}
+
+func (x *TileInstance) UnmarshalJSON(data []byte) error {
+	type plain TileInstance
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, tileInstanceKeys)
+
+	return nil
+}
+
+func (x TileInstance) MarshalJSON() ([]byte, error) {
+	type plain TileInstance
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var levelKeys = map[string]bool{"__bgColor": true, "__bgPos": true, "__neighbours": true, "__smartColor": true, "bgColor": true, "bgPivotX": true, "bgPivotY": true, "bgPos": true, "bgRelPath": true, "externalRelPath": true, "fieldInstances": true, "identifier": true, "iid": true, "layerInstances": true, "pxHei": true, "pxWid": true, "uid": true, "useAutoIdentifier": true, "worldDepth": true, "worldX": true, "worldY": true}
+
+func (x *Level) UnmarshalJSON(data []byte) error {
+	type plain Level
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, levelKeys)
+
+	return nil
+}
+
+func (x Level) MarshalJSON() ([]byte, error) {
+	type plain Level
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var levelBackgroundPositionKeys = map[string]bool{"cropRect": true, "scale": true, "topLeftPx": true}
+
+func (x *LevelBackgroundPosition) UnmarshalJSON(data []byte) error {
+	type plain LevelBackgroundPosition
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, levelBackgroundPositionKeys)
+
+	return nil
+}
+
+func (x LevelBackgroundPosition) MarshalJSON() ([]byte, error) {
+	type plain LevelBackgroundPosition
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var neighbourLevelKeys = map[string]bool{"dir": true, "levelIid": true, "levelUid": true}
+
+func (x *NeighbourLevel) UnmarshalJSON(data []byte) error {
+	type plain NeighbourLevel
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, neighbourLevelKeys)
+
+	return nil
+}
+
+func (x NeighbourLevel) MarshalJSON() ([]byte, error) {
+	type plain NeighbourLevel
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var ldtkTableOfContentEntryKeys = map[string]bool{"identifier": true, "instances": true, "instancesData": true}
+
+func (x *LdtkTableOfContentEntry) UnmarshalJSON(data []byte) error {
+	type plain LdtkTableOfContentEntry
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, ldtkTableOfContentEntryKeys)
+
+	return nil
+}
+
+func (x LdtkTableOfContentEntry) MarshalJSON() ([]byte, error) {
+	type plain LdtkTableOfContentEntry
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var ldtkTocInstanceDataKeys = map[string]bool{"fields": true, "heiPx": true, "iids": true, "widPx": true, "worldX": true, "worldY": true}
+
+func (x *LdtkTocInstanceData) UnmarshalJSON(data []byte) error {
+	type plain LdtkTocInstanceData
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, ldtkTocInstanceDataKeys)
+
+	return nil
+}
+
+func (x LdtkTocInstanceData) MarshalJSON() ([]byte, error) {
+	type plain LdtkTocInstanceData
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
+
+var worldKeys = map[string]bool{"defaultLevelHeight": true, "defaultLevelWidth": true, "identifier": true, "iid": true, "levels": true, "worldGridHeight": true, "worldGridWidth": true, "worldLayout": true}
+
+func (x *World) UnmarshalJSON(data []byte) error {
+	type plain World
+	if err := json.Unmarshal(data, (*plain)(x)); err != nil {
+		return err
+	}
+
+	x.Unknown = unknownKeys(data, worldKeys)
+
+	return nil
+}
+
+func (x World) MarshalJSON() ([]byte, error) {
+	type plain World
+	return marshalWithUnknown(plain(x), x.Unknown)
+}
@@ -0,0 +1,88 @@
+package quicktype
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want map[string]json.RawMessage
+	}{
+		{name: "no members", data: `{}`},
+		{name: "known members", data: `{"a":1,"b":"x"}`},
+		{
+			name: "unknown scalars",
+			data: `{"a":1,"c":-1.5e3,"d":true,"e":null}`,
+			want: map[string]json.RawMessage{"c": json.RawMessage(`-1.5e3`), "d": json.RawMessage(`true`), "e": json.RawMessage(`null`)},
+		},
+		{
+			name: "unknown nested values",
+			data: `{"c":{"a":[1,{"b":"}"}]},"b":[],"d":"\"]"}`,
+			want: map[string]json.RawMessage{"c": json.RawMessage(`{"a":[1,{"b":"}"}]}`), "d": json.RawMessage(`"\"]"`)},
+		},
+		{
+			name: "white space",
+			data: "\n{ \"c\" :\t[ 1, 2 ] ,\r\n \"a\" : 1 }\n",
+			want: map[string]json.RawMessage{"c": json.RawMessage(`[ 1, 2 ]`)},
+		},
+		{
+			name: "escaped key",
+			data: `{"\u0063":2,"\u0061":1}`,
+			want: map[string]json.RawMessage{"c": json.RawMessage(`2`)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unknownKeys([]byte(tt.data), map[string]bool{"a": true, "b": true})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unknownKeys() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnknownRoundTrip(t *testing.T) {
+	data := `{"uid":1,"identifier":"Door","future":{"a":[1,2]},"tags":[]}`
+	var def EntityDefinition
+	if err := json.Unmarshal([]byte(data), &def); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(def.Unknown["future"]); got != `{"a":[1,2]}` {
+		t.Fatalf("Unknown[future] = %s", got)
+	}
+
+	out, err := json.Marshal(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again EntityDefinition
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.Unknown, def.Unknown) {
+		t.Errorf("Unknown after a round trip = %q, want %q", again.Unknown, def.Unknown)
+	}
+}
+
+// BenchmarkUnmarshalLdtkJSON decodes the example project, so that the cost of
+// collecting the unknown keys can be compared with the decoder of earlier
+// versions, which did not collect them.
+func BenchmarkUnmarshalLdtkJSON(b *testing.B) {
+	data, err := os.ReadFile("../example/project.ldtk")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalLdtkJSON(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
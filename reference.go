@@ -0,0 +1,92 @@
+package goldtk
+
+import (
+	"goldtk/quicktype"
+)
+
+// Reference points to an entity instance, along with the layer, level and
+// world containing it. References obtained from a Root resolve through the
+// project index, including into external levels which are loaded on demand.
+type Reference interface {
+	Reference() quicktype.ReferenceToAnEntityInstance
+
+	EntityIid() InstanceIdentifier
+	LayerIid() InstanceIdentifier
+	LevelIid() InstanceIdentifier
+	WorldIid() InstanceIdentifier
+
+	// Entity, Layer, Level and World return the referenced instances, or
+	// false when they do not exist in the project.
+	Entity() (Entity, bool)
+	Layer() (Layer, bool)
+	Level() (Level, bool)
+	World() (World, bool)
+}
+
+type reference struct {
+	inst quicktype.ReferenceToAnEntityInstance
+	idx  *index
+}
+
+func (r reference) Reference() quicktype.ReferenceToAnEntityInstance {
+	return r.inst
+}
+
+func (r reference) EntityIid() InstanceIdentifier {
+	return InstanceIdentifier(r.inst.EntityIid)
+}
+
+func (r reference) LayerIid() InstanceIdentifier {
+	return InstanceIdentifier(r.inst.LayerIid)
+}
+
+func (r reference) LevelIid() InstanceIdentifier {
+	return InstanceIdentifier(r.inst.LevelIid)
+}
+
+func (r reference) WorldIid() InstanceIdentifier {
+	return InstanceIdentifier(r.inst.WorldIid)
+}
+
+func (r reference) Entity() (Entity, bool) {
+	if r.idx == nil {
+		return nil, false
+	}
+
+	return r.idx.entity(r.inst.EntityIid, r.inst.LevelIid)
+}
+
+func (r reference) Layer() (Layer, bool) {
+	if r.idx == nil {
+		return nil, false
+	}
+
+	return r.idx.layer(r.inst.LayerIid, r.inst.LevelIid)
+}
+
+func (r reference) Level() (Level, bool) {
+	if r.idx == nil {
+		return nil, false
+	}
+
+	lvl, err := r.idx.level(r.inst.LevelIid)
+	return lvl, err == nil
+}
+
+func (r reference) World() (World, bool) {
+	if r.idx == nil {
+		return nil, false
+	}
+
+	return r.idx.world(r.inst.WorldIid)
+}
+
+// NewReference wraps a reference to an entity instance. Such a reference is
+// not attached to a project and never resolves; use Root.Reference instead.
+func NewReference(inst quicktype.ReferenceToAnEntityInstance) Reference {
+	return reference{
+		inst: inst,
+	}
+}
+
+var _ Reference = reference{}
@@ -0,0 +1,217 @@
+package goldtk
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"slices"
+)
+
+// RenderOptions selects what a Renderer draws.
+type RenderOptions struct {
+	// Background fills levels with their background color.
+	Background bool
+
+	// IntGrid draws the cells of IntGrid layers which have no auto-layer
+	// tiles, with the color of their value.
+	IntGrid bool
+
+	// Entities draws entities with their tile, or as a rectangle of their
+	// definition color.
+	Entities bool
+
+	// Hidden also draws the layers hidden in the editor.
+	Hidden bool
+}
+
+// DefaultRenderOptions draws levels close to the way the editor shows them.
+var DefaultRenderOptions = RenderOptions{
+	Background: true,
+	IntGrid:    true,
+	Entities:   true,
+}
+
+// Renderer draws levels and layers of a project to images, in level
+// coordinates.
+type Renderer interface {
+	// RenderLevel draws every layer of the level, from the bottom one up.
+	RenderLevel(lvl Level) *image.RGBA
+
+	// RenderLayer draws a single layer of the level over a transparent image
+	// the size of the level.
+	RenderLayer(lvl Level, lyr Layer) *image.RGBA
+}
+
+type renderer struct {
+	root Root
+	opts RenderOptions
+}
+
+func (r renderer) RenderLevel(lvl Level) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, lvl.PxWidth(), lvl.PxHeight()))
+	if r.opts.Background {
+		draw.Draw(img, img.Bounds(), image.NewUniform(lvl.BgColor().RGBA()), image.Point{}, draw.Src)
+	}
+
+	// The first layer instance is the top-most one.
+	layers := slices.Clone(lvl.Layers())
+	slices.Reverse(layers)
+	for _, lyr := range layers {
+		r.drawLayer(img, lyr)
+	}
+
+	return img
+}
+
+func (r renderer) RenderLayer(lvl Level, lyr Layer) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, lvl.PxWidth(), lvl.PxHeight()))
+	r.drawLayer(img, lyr)
+
+	return img
+}
+
+func (r renderer) drawLayer(dst *image.RGBA, lyr Layer) {
+	if !lyr.IsVisible() && !r.opts.Hidden {
+		return
+	}
+
+	opacity := float64(lyr.Opacity())
+	offX, offY := lyr.PxTotalOffsetX(), lyr.PxTotalOffsetY()
+
+	if lyr.Type() == IntGridLayer && r.opts.IntGrid && len(lyr.AutoLayerTiles()) == 0 {
+		r.drawIntGrid(dst, lyr, opacity)
+	}
+
+	if ts, ok := lyr.Tileset().Get(); ok && ts.Image() != nil {
+		size := ts.TileGridSize()
+		for _, t := range append(lyr.GridTiles(), lyr.AutoLayerTiles()...) {
+			sx, sy := t.Src()
+			drawTile(dst,
+				image.Rect(offX+t.X(), offY+t.Y(), offX+t.X()+size, offY+t.Y()+size),
+				ts.Image(), image.Rect(sx, sy, sx+size, sy+size),
+				t.FlipX(), t.FlipY(), opacity*t.Opacity())
+		}
+	}
+
+	if lyr.Type() == EntityLayer && r.opts.Entities {
+		for _, e := range lyr.Entities() {
+			r.drawEntity(dst, e, opacity)
+		}
+	}
+}
+
+func (r renderer) drawIntGrid(dst *image.RGBA, lyr Layer, opacity float64) {
+	def := lyr.Def()
+	if def == nil {
+		return
+	}
+
+	space := lyr.Space()
+	grid := lyr.IntGrid()
+	for cy := 0; cy < grid.Height(); cy++ {
+		for cx := 0; cx < grid.Width(); cx++ {
+			v, ok := def.IntGridValue(grid.At(cx, cy))
+			if !ok {
+				continue
+			}
+
+			cell := space.GridToLevel(GridPoint{CX: cx, CY: cy})
+			fillRect(dst, image.Rect(cell.X, cell.Y, cell.X+space.GridSize, cell.Y+space.GridSize), v.Color().RGBA(), opacity)
+		}
+	}
+}
+
+// drawEntity draws the tile of the entity stretched over its bounds, or falls
+// back to the fill and outline of its definition.
+func (r renderer) drawEntity(dst *image.RGBA, e Entity, opacity float64) {
+	b := e.LevelBounds()
+	bounds := image.Rect(b.X, b.Y, b.X+b.W, b.Y+b.H)
+
+	if rect, ok := e.TileRect().Get(); ok {
+		if ts, ok := r.root.TilesetByUid(Uid(rect.TilesetUid)); ok && ts.Image() != nil {
+			src := image.Rect(int(rect.X), int(rect.Y), int(rect.X+rect.W), int(rect.Y+rect.H))
+			drawTile(dst, bounds, ts.Image(), src, false, false, opacity)
+			return
+		}
+	}
+
+	def := e.Def()
+	if def == nil {
+		return
+	}
+
+	c := def.Color().RGBA()
+	if !def.Hollow() {
+		fillRect(dst, bounds, c, opacity*def.FillOpacity())
+	}
+
+	line := opacity * def.LineOpacity()
+	fillRect(dst, image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Min.Y+1), c, line)
+	fillRect(dst, image.Rect(bounds.Min.X, bounds.Max.Y-1, bounds.Max.X, bounds.Max.Y), c, line)
+	fillRect(dst, image.Rect(bounds.Min.X, bounds.Min.Y+1, bounds.Min.X+1, bounds.Max.Y-1), c, line)
+	fillRect(dst, image.Rect(bounds.Max.X-1, bounds.Min.Y+1, bounds.Max.X, bounds.Max.Y-1), c, line)
+}
+
+// NewRenderer creates a Renderer drawing the levels of r.
+func NewRenderer(r Root, opts RenderOptions) Renderer {
+	return renderer{
+		root: r,
+		opts: opts,
+	}
+}
+
+var _ Renderer = renderer{}
+
+// fillRect blends a color over the area of dst.
+func fillRect(dst draw.Image, r image.Rectangle, c color.RGBA, opacity float64) {
+	draw.DrawMask(dst, r, image.NewUniform(c), image.Point{}, opacityMask(opacity), image.Point{}, draw.Over)
+}
+
+// drawTile blends the src area of img over the dst area, scaling it with the
+// nearest neighbour and flipping it as requested.
+func drawTile(dst draw.Image, r image.Rectangle, img image.Image, src image.Rectangle, flipX, flipY bool, opacity float64) {
+	if r.Empty() || src.Empty() {
+		return
+	}
+
+	tile := tileImage{
+		src:   img,
+		rect:  src,
+		w:     r.Dx(),
+		h:     r.Dy(),
+		flipX: flipX,
+		flipY: flipY,
+	}
+	draw.DrawMask(dst, r, tile, image.Point{}, opacityMask(opacity), image.Point{}, draw.Over)
+}
+
+func opacityMask(opacity float64) image.Image {
+	return image.NewUniform(color.Alpha{A: uint8(max(0, min(1, opacity)) * 255)})
+}
+
+// tileImage is an area of a tileset image, flipped and scaled to w by h.
+type tileImage struct {
+	src          image.Image
+	rect         image.Rectangle
+	w, h         int
+	flipX, flipY bool
+}
+
+func (t tileImage) ColorModel() color.Model {
+	return t.src.ColorModel()
+}
+
+func (t tileImage) Bounds() image.Rectangle {
+	return image.Rect(0, 0, t.w, t.h)
+}
+
+func (t tileImage) At(x, y int) color.Color {
+	if t.flipX {
+		x = t.w - 1 - x
+	}
+	if t.flipY {
+		y = t.h - 1 - y
+	}
+
+	return t.src.At(t.rect.Min.X+x*t.rect.Dx()/t.w, t.rect.Min.Y+y*t.rect.Dy()/t.h)
+}
@@ -2,8 +2,11 @@ package goldtk
 
 import (
 	"fmt"
+	"goldtk/maybe"
 	"goldtk/quicktype"
 	"io/fs"
+	"path"
+	"slices"
 )
 
 type Root interface {
@@ -12,16 +15,83 @@ type Root interface {
 	Levels() []Level
 	Worlds() []World
 	Tilesets() []Tileset
+
+	// Reference attaches a reference to the project, so that it resolves to
+	// the instances it points to.
+	Reference(ref quicktype.ReferenceToAnEntityInstance) Reference
+
+	// ByIid returns the World, Level, Layer or Entity with the given instance
+	// identifier. Layers and entities of external levels are found once their
+	// level has been loaded, or when the table of contents lists them, which
+	// then loads their level. Others are not found; use LoadLevel first.
+	ByIid(iid InstanceIdentifier) (any, bool)
+
+	WorldByIid(iid InstanceIdentifier) (World, bool)
+	LevelByIid(iid InstanceIdentifier) (Level, bool)
+
+	// LoadLevel returns the level with the given instance identifier, reading
+	// its layers first when it is stored in an external file. Unlike
+	// LevelByIid, it returns why the level could not be read. Failures are
+	// not remembered, so the level can be loaded again once its file is fixed.
+	LoadLevel(iid InstanceIdentifier) (Level, error)
+
+	LayerByIid(iid InstanceIdentifier) (Layer, bool)
+	EntityByIid(iid InstanceIdentifier) (Entity, bool)
+
+	EntityDefByUid(uid Uid) (quicktype.EntityDefinition, bool)
+	LayerDefByUid(uid Uid) (quicktype.LayerDefinition, bool)
+	EnumDefByUid(uid Uid) (quicktype.EnumDefinition, bool)
+	TilesetDefByUid(uid Uid) (quicktype.TilesetDefinition, bool)
+
+	// TilesetByUid returns a decoded tileset. Embedded atlases, such as the
+	// LDtk icons, are never decoded.
+	TilesetByUid(uid Uid) (Tileset, bool)
+
+	// FieldDefByUid looks up both entity and level field definitions.
+	FieldDefByUid(uid Uid) (quicktype.FieldDefinition, bool)
+
+	// Defs returns the definitions of the project.
+	Defs() quicktype.Definitions
+
+	// PNGFilePattern is the naming pattern of exported images, when the
+	// project overrides the default one. See ExpandPNGFilePattern.
+	PNGFilePattern() maybe.Value[string]
+
+	// ImageExportMode is how the editor exports images of the levels, and
+	// ExportLevelBg whether level images include the level background.
+	ImageExportMode() quicktype.ImageExportMode
+	ExportLevelBg() bool
+
+	// TOC returns the table of contents of the project, which lists the
+	// entities whose definition has the `exportToToc` option set. See
+	// LoadTOC to read it without loading the levels.
+	TOC() []TOCEntry
+
+	// Migrations returns the changes made to bring the project, saved by an
+	// older version of the editor, to the current format. Changes to
+	// external levels are only listed once their level is loaded.
+	Migrations() []Migration
 }
 
 type root struct {
-	inst quicktype.LdtkJSON
-	ts   []Tileset
+	inst       quicktype.LdtkJSON
+	worlds     []World
+	migrations []Migration
+	idx        *index
 }
 
+// Tilesets returns the tilesets of the project, leaving out embedded atlases
+// and, for projects loaded with LoadLazy, tilesets whose image cannot be
+// decoded.
 func (r root) Tilesets() []Tileset {
-	//TODO implement me
-	panic("implement me")
+	tilesets := make([]Tileset, 0)
+	for _, def := range r.inst.Defs.Tilesets {
+		if ts, err := r.idx.tileset(def.Uid); err == nil {
+			tilesets = append(tilesets, ts)
+		}
+	}
+
+	return tilesets
 }
 
 func (r root) Iid() InstanceIdentifier {
@@ -32,36 +102,178 @@ func (r root) BgColor() Color {
 	return ColorFromHex(r.inst.BgColor)
 }
 
+// Levels returns the levels of every world in the project.
 func (r root) Levels() []Level {
-	lvls := make([]Level, len(r.inst.Levels))
-	for i, l := range r.inst.Levels {
-		lvls[i] = NewLevel(l)
+	lvls := make([]Level, 0)
+	for _, w := range r.worlds {
+		lvls = append(lvls, w.Levels()...)
 	}
 
 	return lvls
 }
 
+// Worlds returns the worlds of the project. Projects without the Multi-worlds
+// option enabled have a single world built from the root level settings.
 func (r root) Worlds() []World {
-	for range r.inst.Worlds {
-		// do thing
+	return r.worlds
+}
+
+func (r root) Reference(ref quicktype.ReferenceToAnEntityInstance) Reference {
+	return reference{inst: ref, idx: r.idx}
+}
+
+func (r root) ByIid(iid InstanceIdentifier) (any, bool) {
+	return r.idx.lookup(string(iid))
+}
+
+func (r root) WorldByIid(iid InstanceIdentifier) (World, bool) {
+	return r.idx.world(string(iid))
+}
+
+func (r root) LevelByIid(iid InstanceIdentifier) (Level, bool) {
+	lvl, err := r.idx.level(string(iid))
+	return lvl, err == nil
+}
+
+func (r root) LoadLevel(iid InstanceIdentifier) (Level, error) {
+	return r.idx.level(string(iid))
+}
+
+func (r root) LayerByIid(iid InstanceIdentifier) (Layer, bool) {
+	return r.idx.layer(string(iid), "")
+}
+
+func (r root) EntityByIid(iid InstanceIdentifier) (Entity, bool) {
+	return r.idx.entity(string(iid), "")
+}
+
+func (r root) EntityDefByUid(uid Uid) (quicktype.EntityDefinition, bool) {
+	def, ok := r.idx.entityDefs[int64(uid)]
+	return def, ok
+}
+
+func (r root) LayerDefByUid(uid Uid) (quicktype.LayerDefinition, bool) {
+	def, ok := r.idx.layerDefs[int64(uid)]
+	return def, ok
+}
+
+func (r root) EnumDefByUid(uid Uid) (quicktype.EnumDefinition, bool) {
+	def, ok := r.idx.enumDefs[int64(uid)]
+	return def, ok
+}
+
+func (r root) TilesetDefByUid(uid Uid) (quicktype.TilesetDefinition, bool) {
+	def, ok := r.idx.tilesetDefs[int64(uid)]
+	return def, ok
+}
+
+func (r root) TilesetByUid(uid Uid) (Tileset, bool) {
+	ts, err := r.idx.tileset(int64(uid))
+	return ts, err == nil
+}
+
+func (r root) FieldDefByUid(uid Uid) (quicktype.FieldDefinition, bool) {
+	def, ok := r.idx.fieldDefs[int64(uid)]
+	return def, ok
+}
+
+func (r root) Defs() quicktype.Definitions {
+	return r.inst.Defs
+}
+
+func (r root) PNGFilePattern() maybe.Value[string] {
+	return maybe.From[string](r.inst.PNGFilePattern)
+}
+
+func (r root) ImageExportMode() quicktype.ImageExportMode {
+	return r.inst.ImageExportMode
+}
+
+func (r root) ExportLevelBg() bool {
+	return r.inst.ExportLevelBg
+}
+
+func (r root) TOC() []TOCEntry {
+	return newTOC(r.inst.Toc, r.idx)
+}
+
+func (r root) Migrations() []Migration {
+	migrations := slices.Clone(r.migrations)
+
+	r.idx.mu.Lock()
+	defer r.idx.mu.Unlock()
+
+	for _, w := range r.worlds {
+		for _, lvl := range w.Levels() {
+			if entry, ok := r.idx.levels[string(lvl.Iid())]; ok {
+				migrations = append(migrations, entry.migrations...)
+			}
+		}
 	}
-	//TODO implement me
-	panic("implement me")
+
+	return migrations
 }
 
+// NewRoot wraps a decoded project. Paths found in the project, such as tileset
+// images and external levels, are opened from the root of sys. Projects saved
+// by older versions of the editor are migrated, see Migrate.
 func NewRoot(ldtk quicktype.LdtkJSON, sys fs.FS) (Root, error) {
-	tilesets := make([]Tileset, 0)
-	for _, def := range ldtk.Defs.Tilesets {
-		ts, err := NewTileset(def, sys)
-		if err != nil {
-			return nil, fmt.Errorf("error creating tileset: %v", err)
-		}
+	return newRoot(ldtk, sys, ".", false)
+}
+
+// Load reads the project file name from sys. Paths found in the project are
+// resolved relative to the directory of the project file.
+func Load(sys fs.FS, name string) (Root, error) {
+	return load(sys, name, false)
+}
+
+// LoadLazy is like Load, but decodes the image of a tileset on its first use
+// rather than with the project, so that a LevelStreamer decodes tilesets
+// along with the first level using them. A tileset whose image cannot be
+// decoded is missing from the project instead of failing the load.
+func LoadLazy(sys fs.FS, name string) (Root, error) {
+	return load(sys, name, true)
+}
+
+func load(sys fs.FS, name string, lazy bool) (Root, error) {
+	data, err := fs.ReadFile(sys, name)
+	if err != nil {
+		return nil, fmt.Errorf("reading project %s: %w", name, err)
+	}
 
-		tilesets = append(tilesets, ts)
+	ldtk, err := quicktype.UnmarshalLdtkJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding project %s: %w", name, err)
 	}
+
+	return newRoot(ldtk, sys, path.Dir(name), lazy)
+}
+
+func newRoot(ldtk quicktype.LdtkJSON, sys fs.FS, dir string, lazy bool) (Root, error) {
+	migrations := Migrate(&ldtk)
+	idx := newIndex(sys, dir)
+
+	idx.addDefs(ldtk.Defs)
+	idx.addTOC(ldtk.Toc)
+	if lazy {
+		idx.addTilesets(ldtk.Defs.Tilesets)
+	} else if _, err := idx.openTilesets(ldtk.Defs.Tilesets); err != nil {
+		return nil, err
+	}
+
+	worlds := make([]World, 0)
+	if len(ldtk.Worlds) == 0 {
+		worlds = append(worlds, idx.addWorld(implicitWorld(ldtk)))
+	}
+	for _, w := range ldtk.Worlds {
+		worlds = append(worlds, idx.addWorld(w))
+	}
+
 	return root{
-		inst: ldtk,
-		ts:   nil,
+		inst:       ldtk,
+		worlds:     worlds,
+		migrations: migrations,
+		idx:        idx,
 	}, nil
 }
 
@@ -0,0 +1,54 @@
+package simple
+
+// levelData is the content of the data.json file of a level.
+type levelData struct {
+	Identifier string `json:"identifier"`
+
+	// UniqueIdentifier is spelled the way LDtk writes it.
+	UniqueIdentifier string `json:"uniqueIdentifer"`
+
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	BgColor string `json:"bgColor"`
+
+	NeighbourLevels []neighbourData `json:"neighbourLevels"`
+	CustomFields    map[string]any  `json:"customFields"`
+
+	// Layers holds the PNG file names of the layers, from the top-most layer
+	// down.
+	Layers []string `json:"layers"`
+
+	// LayerIdentifiers holds the identifiers of the layers in Layers, as
+	// their images follow the PNG file pattern of the project. The editor
+	// leaves it out, its images being named after their layer.
+	LayerIdentifiers []string `json:"layerIdentifiers,omitempty"`
+
+	// Entities holds the entities of the level by identifier.
+	Entities map[string][]entityData `json:"entities"`
+}
+
+type neighbourData struct {
+	LevelIid string `json:"levelIid"`
+	Dir      string `json:"dir"`
+}
+
+type entityData struct {
+	ID           string         `json:"id"`
+	Iid          string         `json:"iid"`
+	Layer        string         `json:"layer"`
+	X            int            `json:"x"`
+	Y            int            `json:"y"`
+	Width        int            `json:"width"`
+	Height       int            `json:"height"`
+	Color        int64          `json:"color"`
+	CustomFields map[string]any `json:"customFields"`
+}
+
+// Well known files of a level folder.
+const (
+	dataFile       = "data.json"
+	compositeFile  = "_composite.png"
+	backgroundFile = "_bg.png"
+)
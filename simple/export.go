@@ -0,0 +1,160 @@
+package simple
+
+import (
+	"encoding/json"
+	"fmt"
+	"goldtk"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// defaultPNGFilePattern names layer images after their layer, unless the
+// project sets its own pattern.
+const defaultPNGFilePattern = "%layer_name"
+
+// Export writes the simplified export of every level of r to fsys, one folder
+// per level named after the level, so levels of different worlds must not
+// share their identifier.
+//
+// Layer images are named after the PNG file pattern of the project, and
+// data.json lists the identifiers of their layers next to their names. Entity
+// layers and hidden layers have no image, and the composite image leaves
+// entities out, as engines place entities from data.json. Background images
+// are not drawn.
+func Export(r goldtk.Root, fsys goldtk.WriteFS) error {
+	if err := goldtk.CheckLevelIdentifiers(r); err != nil {
+		return err
+	}
+
+	pattern := defaultPNGFilePattern
+	if p, ok := r.PNGFilePattern().Get(); ok && p != "" {
+		pattern = p
+	}
+
+	layers := goldtk.NewRenderer(r, goldtk.RenderOptions{IntGrid: true})
+	composite := goldtk.NewRenderer(r, goldtk.RenderOptions{Background: true, IntGrid: true})
+
+	for _, w := range r.Worlds() {
+		for i, lvl := range w.Levels() {
+			levelDir := string(lvl.Identifier())
+
+			data := newLevelData(lvl)
+			for j, lyr := range lvl.Layers() {
+				if lyr.Type() == goldtk.IntGridLayer {
+					name := path.Join(levelDir, string(lyr.Identifier())+".csv")
+					if err := fsys.WriteFile(name, []byte(encodeCSV(lyr.IntGrid()))); err != nil {
+						return fmt.Errorf("writing %s: %w", name, err)
+					}
+				}
+
+				if lyr.Type() == goldtk.EntityLayer || !lyr.IsVisible() {
+					continue
+				}
+
+				file := goldtk.ExpandPNGFilePattern(pattern, goldtk.PNGFileVars{
+					World:    w.Identifier(),
+					Level:    lvl.Identifier(),
+					Layer:    lyr.Identifier(),
+					LevelIdx: i,
+					LayerIdx: j,
+				}) + ".png"
+				if err := goldtk.WriteImage(fsys, path.Join(levelDir, file), layers.RenderLayer(lvl, lyr)); err != nil {
+					return err
+				}
+
+				data.Layers = append(data.Layers, file)
+				data.LayerIdentifiers = append(data.LayerIdentifiers, string(lyr.Identifier()))
+			}
+
+			if err := goldtk.WriteImage(fsys, path.Join(levelDir, compositeFile), composite.RenderLevel(lvl)); err != nil {
+				return err
+			}
+
+			raw, err := json.MarshalIndent(data, "", "\t")
+			if err != nil {
+				return fmt.Errorf("encoding level %s: %w", lvl.Identifier(), err)
+			}
+			name := path.Join(levelDir, dataFile)
+			if err := fsys.WriteFile(name, raw); err != nil {
+				return fmt.Errorf("writing %s: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// newLevelData collects the data.json content of a level, without its layer
+// images.
+func newLevelData(lvl goldtk.Level) levelData {
+	data := levelData{
+		Identifier:       string(lvl.Identifier()),
+		UniqueIdentifier: string(lvl.Iid()),
+		X:                lvl.WorldX(),
+		Y:                lvl.WorldY(),
+		Width:            lvl.PxWidth(),
+		Height:           lvl.PxHeight(),
+		BgColor:          lvl.BgColor().Hex(),
+		NeighbourLevels:  make([]neighbourData, 0),
+		CustomFields:     customFields(lvl.Fields()),
+		Layers:           make([]string, 0),
+		Entities:         make(map[string][]entityData),
+	}
+
+	for _, n := range lvl.Neighbours() {
+		data.NeighbourLevels = append(data.NeighbourLevels, neighbourData{LevelIid: string(n.LevelIid()), Dir: string(n.Dir())})
+	}
+
+	for _, lyr := range lvl.Layers() {
+		for _, e := range lyr.Entities() {
+			var c int64
+			if def := e.Def(); def != nil {
+				rgba := def.Color().RGBA()
+				c = int64(rgba.R)<<16 | int64(rgba.G)<<8 | int64(rgba.B)
+			}
+
+			pos := e.LevelPos()
+			id := string(e.Identifier())
+			data.Entities[id] = append(data.Entities[id], entityData{
+				ID:           id,
+				Iid:          string(e.Iid()),
+				Layer:        string(lyr.Identifier()),
+				X:            pos.X,
+				Y:            pos.Y,
+				Width:        int(e.Width()),
+				Height:       int(e.Height()),
+				Color:        c,
+				CustomFields: customFields(e.Fields()),
+			})
+		}
+	}
+
+	return data
+}
+
+// customFields converts fields to their JSON values, by identifier.
+func customFields(fields []goldtk.Field) map[string]any {
+	custom := make(map[string]any, len(fields))
+	for _, f := range fields {
+		custom[string(f.Identifier())] = goldtk.FieldJSON(f)
+	}
+
+	return custom
+}
+
+// encodeCSV writes the values of an IntGrid layer, one row per line.
+func encodeCSV(grid goldtk.IntGrid) string {
+	var b strings.Builder
+	for cy := 0; cy < grid.Height(); cy++ {
+		for cx := 0; cx < grid.Width(); cx++ {
+			if cx > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(strconv.Itoa(grid.At(cx, cy)))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
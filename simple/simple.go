@@ -0,0 +1,506 @@
+// Package simple reads and writes the "super simple export" of LDtk projects,
+// which stores every level in its own folder as a data.json file, PNG images
+// of the layers and CSV files of the IntGrid layers.
+package simple
+
+import (
+	"encoding/json"
+	"fmt"
+	"goldtk"
+	"goldtk/quicktype"
+	"image"
+	_ "image/png"
+	"io/fs"
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Project is the set of levels found in a simplified export.
+type Project interface {
+	Levels() []Level
+
+	LevelByIdentifier(id goldtk.Identifier) (Level, bool)
+	LevelByIid(iid goldtk.InstanceIdentifier) (Level, bool)
+}
+
+type project struct {
+	levels []Level
+}
+
+func (p project) Levels() []Level {
+	return p.levels
+}
+
+func (p project) LevelByIdentifier(id goldtk.Identifier) (Level, bool) {
+	i := slices.IndexFunc(p.levels, func(l Level) bool { return l.Identifier() == id })
+	if i < 0 {
+		return nil, false
+	}
+
+	return p.levels[i], true
+}
+
+func (p project) LevelByIid(iid goldtk.InstanceIdentifier) (Level, bool) {
+	i := slices.IndexFunc(p.levels, func(l Level) bool { return l.Iid() == iid })
+	if i < 0 {
+		return nil, false
+	}
+
+	return p.levels[i], true
+}
+
+var _ Project = project{}
+
+// Load reads the simplified export in the folder dir of sys, usually named
+// `simplified`. Every sub-folder holding a data.json file is a level.
+//
+// The export does not store field types, so they are guessed from the field
+// values. Use LoadWithDefs when the project definitions are at hand.
+func Load(sys fs.FS, dir string) (Project, error) {
+	return LoadWithDefs(sys, dir, quicktype.Definitions{})
+}
+
+// LoadWithDefs is like Load, but types the custom fields after the field
+// definitions of defs.
+func LoadWithDefs(sys fs.FS, dir string, defs quicktype.Definitions) (Project, error) {
+	entries, err := fs.ReadDir(sys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+
+	levels := make([]Level, 0)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		levelDir := path.Join(dir, e.Name())
+		if _, err := fs.Stat(sys, path.Join(levelDir, dataFile)); err != nil {
+			continue
+		}
+
+		lvl, err := loadLevel(sys, levelDir, newFieldDefs(defs))
+		if err != nil {
+			return nil, err
+		}
+
+		levels = append(levels, lvl)
+	}
+
+	return project{levels: levels}, nil
+}
+
+// Level is a level of a simplified export.
+type Level interface {
+	Identifier() goldtk.Identifier
+	Iid() goldtk.InstanceIdentifier
+
+	BgColor() goldtk.Color
+
+	PxWidth() int
+	PxHeight() int
+
+	WorldX() int
+	WorldY() int
+
+	Neighbours() []goldtk.Neighbor
+
+	// Layers returns the layers of the level, from the top-most one down.
+	Layers() []Layer
+
+	// LayerByIdentifier returns the layer with the given identifier.
+	LayerByIdentifier(id goldtk.Identifier) (Layer, bool)
+
+	// Entities returns the entities of every layer, by identifier then in
+	// export order.
+	Entities() []Entity
+
+	// Composite returns the image of every layer drawn over the background.
+	Composite() (image.Image, error)
+
+	// Background returns the background image of the level, which only
+	// exists when the level has one.
+	Background() (image.Image, error)
+
+	Fields() []goldtk.Field
+}
+
+type level struct {
+	data   levelData
+	sys    fs.FS
+	dir    string
+	layers []Layer
+	defs   fieldDefs
+}
+
+func (l level) Identifier() goldtk.Identifier {
+	return goldtk.Identifier(l.data.Identifier)
+}
+
+func (l level) Iid() goldtk.InstanceIdentifier {
+	return goldtk.InstanceIdentifier(l.data.UniqueIdentifier)
+}
+
+func (l level) BgColor() goldtk.Color {
+	return goldtk.ColorFromHex(l.data.BgColor)
+}
+
+func (l level) PxWidth() int {
+	return l.data.Width
+}
+
+func (l level) PxHeight() int {
+	return l.data.Height
+}
+
+func (l level) WorldX() int {
+	return l.data.X
+}
+
+func (l level) WorldY() int {
+	return l.data.Y
+}
+
+func (l level) Neighbours() []goldtk.Neighbor {
+	neighbors := make([]goldtk.Neighbor, 0)
+	for _, n := range l.data.NeighbourLevels {
+		neighbors = append(neighbors, goldtk.NewNeighbor(quicktype.NeighbourLevel{Dir: n.Dir, LevelIid: n.LevelIid}))
+	}
+
+	return neighbors
+}
+
+func (l level) Layers() []Layer {
+	return l.layers
+}
+
+func (l level) LayerByIdentifier(id goldtk.Identifier) (Layer, bool) {
+	i := slices.IndexFunc(l.layers, func(lyr Layer) bool { return lyr.Identifier() == id })
+	if i < 0 {
+		return nil, false
+	}
+
+	return l.layers[i], true
+}
+
+func (l level) Entities() []Entity {
+	ids := make([]string, 0, len(l.data.Entities))
+	for id := range l.data.Entities {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	entities := make([]Entity, 0)
+	for _, id := range ids {
+		for _, e := range l.data.Entities[id] {
+			entities = append(entities, entity{data: e, defs: l.defs[e.ID]})
+		}
+	}
+
+	return entities
+}
+
+func (l level) Composite() (image.Image, error) {
+	return decodeImage(l.sys, path.Join(l.dir, compositeFile))
+}
+
+func (l level) Background() (image.Image, error) {
+	return decodeImage(l.sys, path.Join(l.dir, backgroundFile))
+}
+
+func (l level) Fields() []goldtk.Field {
+	return newFields(l.data.CustomFields, l.defs[""])
+}
+
+var _ Level = level{}
+
+// LoadLevel reads the level folder dir of sys, guessing the field types as
+// Load does.
+func LoadLevel(sys fs.FS, dir string) (Level, error) {
+	return loadLevel(sys, dir, nil)
+}
+
+func loadLevel(sys fs.FS, dir string, defs fieldDefs) (Level, error) {
+	name := path.Join(dir, dataFile)
+	raw, err := fs.ReadFile(sys, name)
+	if err != nil {
+		return nil, fmt.Errorf("reading level %s: %w", name, err)
+	}
+
+	var data levelData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("decoding level %s: %w", name, err)
+	}
+
+	layers := make([]Layer, 0)
+	for i, file := range data.Layers {
+		id := strings.TrimSuffix(file, path.Ext(file))
+		if i < len(data.LayerIdentifiers) {
+			id = data.LayerIdentifiers[i]
+		}
+		layers = append(layers, layer{id: goldtk.Identifier(id), sys: sys, png: path.Join(dir, file)})
+	}
+
+	// IntGrid layers have a CSV file next to their image, and possibly no
+	// image at all when they have nothing to draw.
+	entries, err := fs.ReadDir(sys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading level %s: %w", dir, err)
+	}
+
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".csv")
+		if !ok || e.IsDir() {
+			continue
+		}
+
+		csv, err := fs.ReadFile(sys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading level %s: %w", dir, err)
+		}
+
+		grid, err := decodeCSV(string(csv))
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", path.Join(dir, e.Name()), err)
+		}
+
+		i := slices.IndexFunc(layers, func(lyr Layer) bool { return lyr.Identifier() == goldtk.Identifier(id) })
+		if i < 0 {
+			layers = append(layers, layer{id: goldtk.Identifier(id), sys: sys, intGrid: grid})
+			continue
+		}
+
+		lyr := layers[i].(layer)
+		lyr.intGrid = grid
+		layers[i] = lyr
+	}
+
+	return level{data: data, sys: sys, dir: dir, layers: layers, defs: defs}, nil
+}
+
+// Layer is a layer of a simplified export level.
+type Layer interface {
+	Identifier() goldtk.Identifier
+
+	// Image returns the image of the layer, the size of the level.
+	Image() (image.Image, error)
+
+	// IntGrid returns the values of an IntGrid layer, or nil for other
+	// layers.
+	IntGrid() goldtk.IntGrid
+}
+
+type layer struct {
+	id      goldtk.Identifier
+	sys     fs.FS
+	png     string
+	intGrid goldtk.IntGrid
+}
+
+func (l layer) Identifier() goldtk.Identifier {
+	return l.id
+}
+
+func (l layer) Image() (image.Image, error) {
+	if l.png == "" {
+		return nil, fmt.Errorf("layer %s has no image: %w", l.id, fs.ErrNotExist)
+	}
+
+	return decodeImage(l.sys, l.png)
+}
+
+func (l layer) IntGrid() goldtk.IntGrid {
+	return l.intGrid
+}
+
+var _ Layer = layer{}
+
+// Entity is an entity of a simplified export level.
+type Entity interface {
+	Identifier() goldtk.Identifier
+	Iid() goldtk.InstanceIdentifier
+
+	// Layer is the identifier of the layer holding the entity.
+	Layer() goldtk.Identifier
+
+	// LevelPos is the position of the entity pivot in the level.
+	LevelPos() goldtk.LevelPoint
+
+	Width() int
+	Height() int
+	Size() (width, height int)
+
+	// Color is the smart color of the entity.
+	Color() goldtk.Color
+
+	Fields() []goldtk.Field
+}
+
+type entity struct {
+	data entityData
+	defs map[string]quicktype.FieldDefinition
+}
+
+func (e entity) Identifier() goldtk.Identifier {
+	return goldtk.Identifier(e.data.ID)
+}
+
+func (e entity) Iid() goldtk.InstanceIdentifier {
+	return goldtk.InstanceIdentifier(e.data.Iid)
+}
+
+func (e entity) Layer() goldtk.Identifier {
+	return goldtk.Identifier(e.data.Layer)
+}
+
+func (e entity) LevelPos() goldtk.LevelPoint {
+	return goldtk.LevelPoint{X: e.data.X, Y: e.data.Y}
+}
+
+func (e entity) Width() int {
+	return e.data.Width
+}
+
+func (e entity) Height() int {
+	return e.data.Height
+}
+
+func (e entity) Size() (width, height int) {
+	return e.data.Width, e.data.Height
+}
+
+func (e entity) Color() goldtk.Color {
+	return goldtk.ColorFromInt64(e.data.Color)
+}
+
+func (e entity) Fields() []goldtk.Field {
+	return newFields(e.data.CustomFields, e.defs)
+}
+
+var _ Entity = entity{}
+
+// fieldDefs are the field definitions of a project, by identifier: level
+// fields under the empty scope, and entity fields under the entity
+// identifier.
+type fieldDefs map[string]map[string]quicktype.FieldDefinition
+
+func newFieldDefs(defs quicktype.Definitions) fieldDefs {
+	byScope := func(fields []quicktype.FieldDefinition) map[string]quicktype.FieldDefinition {
+		m := make(map[string]quicktype.FieldDefinition, len(fields))
+		for _, f := range fields {
+			m[f.Identifier] = f
+		}
+		return m
+	}
+
+	scopes := fieldDefs{"": byScope(defs.LevelFields)}
+	for _, e := range defs.Entities {
+		scopes[e.Identifier] = byScope(e.FieldDefs)
+	}
+
+	return scopes
+}
+
+// newFields converts custom fields, sorted by identifier. Fields take the
+// type of their definition in defs, or a type guessed from their JSON value
+// when the project definitions are unknown.
+func newFields(custom map[string]any, defs map[string]quicktype.FieldDefinition) []goldtk.Field {
+	ids := make([]string, 0, len(custom))
+	for id := range custom {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	fields := make([]goldtk.Field, 0, len(ids))
+	for _, id := range ids {
+		inst := quicktype.FieldInstance{Identifier: id, Value: custom[id]}
+		if def, ok := defs[id]; ok {
+			inst.Type, inst.DefUid = def.Type, def.Uid
+		} else {
+			inst.Type = guessType(custom[id])
+		}
+
+		fields = append(fields, goldtk.NewField(inst))
+	}
+
+	return fields
+}
+
+// guessType guesses the field type of a raw JSON value: whole numbers are
+// Int, other numbers Float, strings String, and objects Point, EntityRef or
+// Tile. Int values also answer Float64, as a Float field holding a whole
+// number cannot be told apart from an Int field.
+func guessType(raw any) string {
+	switch v := raw.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return "Int"
+		}
+		return "Float"
+	case bool:
+		return "Bool"
+	case string:
+		return "String"
+	case []any:
+		for _, item := range v {
+			if inner := guessType(item); inner != "" {
+				return "Array<" + inner + ">"
+			}
+		}
+		return "Array<>"
+	case map[string]any:
+		switch {
+		case v["cx"] != nil:
+			return "Point"
+		case v["entityIid"] != nil:
+			return "EntityRef"
+		case v["tilesetUid"] != nil:
+			return "Tile"
+		}
+	}
+
+	return ""
+}
+
+// decodeCSV reads an IntGrid CSV file, one row of values per line.
+func decodeCSV(text string) (goldtk.IntGrid, error) {
+	width, height := 0, 0
+	values := make([]int64, 0)
+	for _, line := range strings.Split(text, "\n") {
+		cells := strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\r' || r == '\t' })
+		if len(cells) == 0 {
+			continue
+		}
+
+		for _, c := range cells {
+			v, err := strconv.ParseInt(c, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+
+		width = max(width, len(cells))
+		height++
+	}
+
+	return goldtk.NewIntGrid(width, height, values), nil
+}
+
+func decodeImage(sys fs.FS, name string) (image.Image, error) {
+	f, err := sys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", name, err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", name, err)
+	}
+
+	return img, nil
+}
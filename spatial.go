@@ -0,0 +1,135 @@
+package goldtk
+
+import "sort"
+
+// levelIndex is a uniform grid over world space. Each cell holds the indexes of
+// the levels overlapping it, so point and rectangle queries only inspect levels
+// near the query instead of every level in the world.
+type levelIndex struct {
+	cellW, cellH int
+	cells        map[cellKey][]int
+
+	// bounds holds the cell range covered by levels, to clamp queries.
+	bounds struct{ minX, minY, maxX, maxY int }
+}
+
+type cellKey struct {
+	cx, cy int
+}
+
+// newLevelIndex buckets the rectangles of the levels into cells of the world
+// grid size. Worlds without a grid (eg. Free layouts) use the average level
+// size instead.
+func newLevelIndex(rects []WorldRect, gridWidth, gridHeight int) levelIndex {
+	cellW, cellH := gridWidth, gridHeight
+	if cellW <= 0 || cellH <= 0 {
+		cellW, cellH = averageLevelSize(rects)
+	}
+
+	idx := levelIndex{
+		cellW: cellW,
+		cellH: cellH,
+		cells: make(map[cellKey][]int),
+	}
+
+	first := true
+	for i, r := range rects {
+		if r.W <= 0 || r.H <= 0 {
+			continue
+		}
+
+		idx.eachCell(r, func(k cellKey) {
+			idx.cells[k] = append(idx.cells[k], i)
+
+			if first {
+				idx.bounds.minX, idx.bounds.maxX = k.cx, k.cx
+				idx.bounds.minY, idx.bounds.maxY = k.cy, k.cy
+				first = false
+			}
+			idx.bounds.minX = min(idx.bounds.minX, k.cx)
+			idx.bounds.minY = min(idx.bounds.minY, k.cy)
+			idx.bounds.maxX = max(idx.bounds.maxX, k.cx)
+			idx.bounds.maxY = max(idx.bounds.maxY, k.cy)
+		})
+	}
+
+	return idx
+}
+
+// at returns the candidate levels for the world pixel x, y.
+func (idx levelIndex) at(x, y int) []int {
+	return idx.cells[cellKey{floorDiv(x, idx.cellW), floorDiv(y, idx.cellH)}]
+}
+
+// in returns the candidate levels for the rectangle, without duplicates and in
+// level order.
+func (idx levelIndex) in(r WorldRect) []int {
+	if r.W <= 0 || r.H <= 0 || len(idx.cells) == 0 {
+		return nil
+	}
+
+	seen := make(map[int]struct{})
+	idx.eachCell(idx.clamp(r), func(k cellKey) {
+		for _, i := range idx.cells[k] {
+			seen[i] = struct{}{}
+		}
+	})
+
+	result := make([]int, 0, len(seen))
+	for i := range seen {
+		result = append(result, i)
+	}
+	sort.Ints(result)
+
+	return result
+}
+
+func (idx levelIndex) eachCell(r WorldRect, fn func(cellKey)) {
+	if r.W <= 0 || r.H <= 0 {
+		return
+	}
+
+	minX, minY := floorDiv(r.X, idx.cellW), floorDiv(r.Y, idx.cellH)
+	maxX, maxY := floorDiv(r.X+r.W-1, idx.cellW), floorDiv(r.Y+r.H-1, idx.cellH)
+
+	for cy := minY; cy <= maxY; cy++ {
+		for cx := minX; cx <= maxX; cx++ {
+			fn(cellKey{cx, cy})
+		}
+	}
+}
+
+// clamp shrinks the rectangle to the indexed area, so that huge queries do
+// not walk through empty cells.
+func (idx levelIndex) clamp(r WorldRect) WorldRect {
+	minX := max(r.X, idx.bounds.minX*idx.cellW)
+	minY := max(r.Y, idx.bounds.minY*idx.cellH)
+	maxX := min(r.X+r.W, (idx.bounds.maxX+1)*idx.cellW)
+	maxY := min(r.Y+r.H, (idx.bounds.maxY+1)*idx.cellH)
+
+	return WorldRect{X: minX, Y: minY, W: max(maxX-minX, 0), H: max(maxY-minY, 0)}
+}
+
+func averageLevelSize(rects []WorldRect) (width, height int) {
+	if len(rects) == 0 {
+		return 1, 1
+	}
+
+	for _, r := range rects {
+		width += r.W
+		height += r.H
+	}
+
+	return max(width/len(rects), 1), max(height/len(rects), 1)
+}
+
+// floorDiv divides rounding towards negative infinity, as levels may be placed
+// at negative world coordinates.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+
+	return q
+}
@@ -0,0 +1,371 @@
+package goldtk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goldtk/quicktype"
+	"io"
+	"io/fs"
+	"path"
+)
+
+// LevelDecoder reads the levels of a project one at a time, without holding
+// the whole project in memory. Only the definitions, the tilesets and the
+// current level are kept.
+//
+// Levels come from the `levels` array of the project, then from the `levels`
+// array of every world. Levels stored in external files are read along the
+// way, so that every level has its layers.
+type LevelDecoder interface {
+	// Defs returns the definitions of the project, decoded before any level.
+	Defs() quicktype.Definitions
+
+	// Tilesets returns the decoded tilesets of the project.
+	Tilesets() []Tileset
+
+	// Next decodes the next level, and returns io.EOF after the last one.
+	// References of the levels only resolve to definitions, as other levels
+	// are not kept.
+	Next() (Level, error)
+}
+
+// decoderState is where a LevelDecoder stands in the project.
+type decoderState int
+
+const (
+	// inProject is between the keys of the project object.
+	inProject decoderState = iota
+
+	// inWorlds is between the worlds of the `worlds` array, and inWorld
+	// between the keys of a world.
+	inWorlds
+	inWorld
+
+	// inLevels is between the levels of a `levels` array, of the project
+	// when world is false.
+	inLevels
+
+	// done is after the end of the project.
+	done
+)
+
+type levelDecoder struct {
+	dec      *json.Decoder
+	idx      *index
+	defs     quicktype.Definitions
+	tilesets []Tileset
+
+	state decoderState
+	world bool
+
+	// iid and dummyWorldIid are those of the project, and worldIid the iid of
+	// the world being read. Levels without an iid derive theirs from their
+	// world and position, as Migrate does: worlds counts the worlds read, and
+	// levels the levels read from the current array.
+	iid, dummyWorldIid, worldIid string
+	worlds, levels               int
+}
+
+func (d *levelDecoder) Defs() quicktype.Definitions {
+	return d.defs
+}
+
+func (d *levelDecoder) Tilesets() []Tileset {
+	return d.tilesets
+}
+
+func (d *levelDecoder) Next() (Level, error) {
+	for {
+		switch d.state {
+		case done:
+			return nil, io.EOF
+
+		case inLevels:
+			if !d.dec.More() {
+				if err := d.delim(']'); err != nil {
+					return nil, err
+				}
+				d.state = inProject
+				if d.world {
+					d.state = inWorld
+				}
+				continue
+			}
+
+			return d.level()
+
+		case inWorld:
+			if !d.dec.More() {
+				if err := d.delim('}'); err != nil {
+					return nil, err
+				}
+				d.state = inWorlds
+				continue
+			}
+
+			key, err := d.key()
+			if err != nil {
+				return nil, err
+			}
+			switch key {
+			case "levels":
+				if err := d.delim('['); err != nil {
+					return nil, err
+				}
+				d.state, d.world, d.levels = inLevels, true, 0
+				continue
+			case "iid":
+				var iid string
+				if err := d.dec.Decode(&iid); err != nil {
+					return nil, fmt.Errorf("decoding world iid: %w", err)
+				}
+				if iid != "" {
+					d.worldIid = iid
+				}
+				continue
+			}
+			if err := d.skip(); err != nil {
+				return nil, err
+			}
+
+		case inWorlds:
+			if !d.dec.More() {
+				if err := d.delim(']'); err != nil {
+					return nil, err
+				}
+				d.state = inProject
+				continue
+			}
+
+			if err := d.delim('{'); err != nil {
+				return nil, err
+			}
+			d.state = inWorld
+			d.worldIid = derivedIid(d.projectIid(), "world", d.worlds)
+			d.worlds++
+
+		case inProject:
+			if !d.dec.More() {
+				if err := d.delim('}'); err != nil {
+					return nil, err
+				}
+				d.state = done
+				continue
+			}
+
+			key, err := d.key()
+			if err != nil {
+				return nil, err
+			}
+
+			switch key {
+			case "levels":
+				if err := d.delim('['); err != nil {
+					return nil, err
+				}
+				d.state, d.world, d.levels = inLevels, false, 0
+			case "worlds":
+				if err := d.delim('['); err != nil {
+					return nil, err
+				}
+				d.state = inWorlds
+			default:
+				if err := d.member(key); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+// level decodes a level of a `levels` array, along with its external file.
+func (d *levelDecoder) level() (Level, error) {
+	var inst quicktype.Level
+	if err := d.dec.Decode(&inst); err != nil {
+		return nil, fmt.Errorf("decoding level: %w", err)
+	}
+
+	if inst.ExternalRelPath != nil && inst.LayerInstances == nil {
+		ext, err := d.idx.readLevel(*inst.ExternalRelPath)
+		if err != nil {
+			return nil, err
+		}
+
+		ext.ExternalRelPath = inst.ExternalRelPath
+		if ext.Iid == "" {
+			ext.Iid = inst.Iid
+		}
+		inst = ext
+	}
+
+	worldIid := d.worldIid
+	if !d.world {
+		worldIid = d.dummyWorldIid
+		if worldIid == "" {
+			worldIid = derivedIid(d.projectIid(), "world", 0)
+		}
+	}
+	migrateLevel(&inst, worldIid, d.levels)
+	d.levels++
+
+	return newLevel(inst, d.idx), nil
+}
+
+// projectIid returns the iid of the project, or the one Migrate gives to
+// projects without one.
+func (d *levelDecoder) projectIid() string {
+	if d.iid == "" {
+		return projectIid()
+	}
+
+	return d.iid
+}
+
+// member reads a member of the project which is not an array of levels,
+// keeping the identifiers levels derive theirs from.
+func (d *levelDecoder) member(key string) error {
+	switch key {
+	case "iid":
+		if err := d.dec.Decode(&d.iid); err != nil {
+			return fmt.Errorf("decoding project iid: %w", err)
+		}
+	case "dummyWorldIid":
+		if err := d.dec.Decode(&d.dummyWorldIid); err != nil {
+			return fmt.Errorf("decoding project dummyWorldIid: %w", err)
+		}
+	default:
+		return d.skip()
+	}
+
+	return nil
+}
+
+// key reads the key of an object member.
+func (d *levelDecoder) key() (string, error) {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return "", fmt.Errorf("decoding project: %w", err)
+	}
+
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("decoding project: unexpected %v", tok)
+	}
+
+	return key, nil
+}
+
+// delim reads the delimiter want.
+func (d *levelDecoder) delim(want json.Delim) error {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return fmt.Errorf("decoding project: %w", err)
+	}
+
+	if got, ok := tok.(json.Delim); !ok || got != want {
+		return fmt.Errorf("decoding project: expected %v, got %v", want, tok)
+	}
+
+	return nil
+}
+
+// skip reads over a value without decoding it.
+func (d *levelDecoder) skip() error {
+	depth := 0
+	for {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return fmt.Errorf("decoding project: %w", err)
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+var _ LevelDecoder = &levelDecoder{}
+
+// NewLevelDecoder reads the project in r up to its definitions, and decodes
+// its tilesets. Paths found in the project are resolved relative to dir in
+// sys.
+func NewLevelDecoder(r io.Reader, sys fs.FS, dir string) (LevelDecoder, error) {
+	d := &levelDecoder{
+		dec: json.NewDecoder(r),
+		idx: newIndex(sys, dir),
+	}
+
+	if err := d.delim('{'); err != nil {
+		return nil, err
+	}
+
+	for {
+		if !d.dec.More() {
+			return nil, fmt.Errorf("decoding project: no defs")
+		}
+
+		key, err := d.key()
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case "defs":
+			if err := d.dec.Decode(&d.defs); err != nil {
+				return nil, fmt.Errorf("decoding project defs: %w", err)
+			}
+
+			d.idx.addDefs(d.defs)
+			if d.tilesets, err = d.idx.openTilesets(d.defs.Tilesets); err != nil {
+				return nil, err
+			}
+
+			return d, nil
+		case "levels", "worlds":
+			// The editor always writes the definitions first.
+			return nil, fmt.Errorf("decoding project: %s come before defs", key)
+		default:
+			if err := d.member(key); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// StreamLevels reads the project file name from sys, and calls fn with every
+// level in turn, until fn returns an error. Paths found in the project are
+// resolved relative to the directory of the project file.
+func StreamLevels(sys fs.FS, name string, fn func(Level) error) error {
+	f, err := sys.Open(name)
+	if err != nil {
+		return fmt.Errorf("reading project %s: %w", name, err)
+	}
+	defer f.Close()
+
+	d, err := NewLevelDecoder(f, sys, path.Dir(name))
+	if err != nil {
+		return fmt.Errorf("reading project %s: %w", name, err)
+	}
+
+	for {
+		lvl, err := d.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading project %s: %w", name, err)
+		}
+
+		if err := fn(lvl); err != nil {
+			return err
+		}
+	}
+}
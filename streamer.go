@@ -0,0 +1,340 @@
+package goldtk
+
+import (
+	"io/fs"
+	"math"
+	"sync"
+)
+
+// StreamerOptions controls which levels a LevelStreamer keeps loaded.
+type StreamerOptions struct {
+	// Radius is the world distance in pixels from the focus point under
+	// which levels are loaded.
+	Radius int
+
+	// NeighbourDepth is the number of `Neighbours` hops from the levels
+	// under the focus point under which levels are loaded, whatever their
+	// distance.
+	NeighbourDepth int
+
+	// Budget is the memory, in bytes, used by loaded levels before the least
+	// recently used ones are unloaded. Levels around the focus point are
+	// never unloaded, and levels stored in the project file use no budget.
+	Budget int64
+
+	// OnLoad and OnUnload are called when a level is loaded and unloaded, in
+	// order, from a goroutine of the streamer. The level given to OnUnload
+	// keeps its layers, and never reads its file again.
+	OnLoad   func(Level)
+	OnUnload func(Level)
+}
+
+// LevelStreamer keeps the levels of a world around a focus point loaded,
+// reading levels stored in external files in the background. The tilesets of
+// a level are decoded along with it when the project is loaded with
+// LoadLazy.
+type LevelStreamer interface {
+	// Focus moves the focus point, starts loading the levels around it, and
+	// unloads the least recently used levels over budget.
+	Focus(p WorldPoint)
+
+	// Level returns a loaded level, and marks it as used.
+	Level(iid InstanceIdentifier) (Level, bool)
+
+	// Loaded returns the loaded levels.
+	Loaded() []Level
+
+	// Wait blocks until pending loads and callbacks are done.
+	Wait()
+}
+
+// streamedLevel is the state of a level of the streamer.
+type streamedLevel struct {
+	iid        string
+	bounds     WorldRect
+	neighbours []string
+
+	// size is an estimate of the memory used by the loaded level, from the
+	// size of its file.
+	size int64
+
+	wanted  bool
+	loading bool
+	loaded  bool
+
+	// used is the tick of the last use of the level.
+	used uint64
+}
+
+// streamerEvent is a pending callback.
+type streamerEvent struct {
+	lvl    Level
+	loaded bool
+}
+
+type levelStreamer struct {
+	root Root
+	idx  *index
+	opts StreamerOptions
+
+	mu     sync.Mutex
+	levels map[string]*streamedLevel
+	order  []string
+	tick   uint64
+
+	events      []streamerEvent
+	dispatching bool
+
+	wg sync.WaitGroup
+}
+
+func (s *levelStreamer) Focus(p WorldPoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tick++
+	wanted := s.around(p)
+
+	for _, iid := range s.order {
+		lvl := s.levels[iid]
+		lvl.wanted = wanted[iid]
+		if !lvl.wanted {
+			continue
+		}
+
+		lvl.used = s.tick
+		if !lvl.loaded && !lvl.loading {
+			lvl.loading = true
+			s.wg.Add(1)
+			go s.load(lvl)
+		}
+	}
+
+	s.evict()
+}
+
+// around returns the levels within the radius of p, and the neighbours of
+// the levels under p. The lock must be held.
+func (s *levelStreamer) around(p WorldPoint) map[string]bool {
+	wanted := make(map[string]bool)
+	frontier := make([]string, 0)
+	for _, iid := range s.order {
+		d := distance(s.levels[iid].bounds, p)
+		if d <= float64(s.opts.Radius) {
+			wanted[iid] = true
+		}
+		if d == 0 {
+			frontier = append(frontier, iid)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for depth := 0; depth < s.opts.NeighbourDepth && len(frontier) > 0; depth++ {
+		next := make([]string, 0)
+		for _, iid := range frontier {
+			seen[iid] = true
+			for _, n := range s.levels[iid].neighbours {
+				if _, ok := s.levels[n]; !ok || seen[n] {
+					continue
+				}
+
+				wanted[n] = true
+				next = append(next, n)
+			}
+		}
+		frontier = next
+	}
+
+	return wanted
+}
+
+// distance returns the world distance from p to the closest pixel of r.
+func distance(r WorldRect, p WorldPoint) float64 {
+	dx := max(r.X-p.X, 0, p.X-(r.X+r.W-1))
+	dy := max(r.Y-p.Y, 0, p.Y-(r.Y+r.H-1))
+
+	return math.Hypot(float64(dx), float64(dy))
+}
+
+// load reads a level and the tilesets of its layers, without holding the
+// lock.
+func (s *levelStreamer) load(state *streamedLevel) {
+	defer s.wg.Done()
+
+	lvl, ok := s.root.LevelByIid(InstanceIdentifier(state.iid))
+	if ok {
+		for _, lyr := range lvl.Layers() {
+			lyr.Tileset()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state.loading = false
+	state.loaded = ok
+	if ok {
+		s.notify(streamerEvent{lvl: lvl, loaded: true})
+	}
+
+	s.evict()
+}
+
+// evict unloads the least recently used levels while the loaded levels are
+// over budget. The lock must be held.
+func (s *levelStreamer) evict() {
+	var total int64
+	for _, lvl := range s.levels {
+		if lvl.loaded {
+			total += lvl.size
+		}
+	}
+
+	for total > s.opts.Budget {
+		var oldest *streamedLevel
+		for _, iid := range s.order {
+			lvl := s.levels[iid]
+			if !lvl.loaded || lvl.wanted || lvl.size == 0 {
+				continue
+			}
+			if oldest == nil || lvl.used < oldest.used {
+				oldest = lvl
+			}
+		}
+		if oldest == nil {
+			return
+		}
+
+		lvl, ok := s.idx.unload(oldest.iid)
+
+		oldest.loaded = false
+		total -= oldest.size
+		if ok {
+			s.notify(streamerEvent{lvl: lvl, loaded: false})
+		}
+	}
+}
+
+// notify queues a callback, and starts dispatching callbacks when needed.
+// The lock must be held.
+func (s *levelStreamer) notify(e streamerEvent) {
+	if (e.loaded && s.opts.OnLoad == nil) || (!e.loaded && s.opts.OnUnload == nil) {
+		return
+	}
+
+	s.events = append(s.events, e)
+	if s.dispatching {
+		return
+	}
+
+	s.dispatching = true
+	s.wg.Add(1)
+	go s.dispatch()
+}
+
+// dispatch calls the queued callbacks in order, without holding the lock so
+// that callbacks may use the streamer.
+func (s *levelStreamer) dispatch() {
+	defer s.wg.Done()
+
+	for {
+		s.mu.Lock()
+		if len(s.events) == 0 {
+			s.dispatching = false
+			s.mu.Unlock()
+			return
+		}
+
+		e := s.events[0]
+		s.events = s.events[1:]
+		s.mu.Unlock()
+
+		if e.loaded {
+			s.opts.OnLoad(e.lvl)
+		} else {
+			s.opts.OnUnload(e.lvl)
+		}
+	}
+}
+
+func (s *levelStreamer) Level(iid InstanceIdentifier) (Level, bool) {
+	s.mu.Lock()
+	lvl, ok := s.levels[string(iid)]
+	if !ok || !lvl.loaded {
+		s.mu.Unlock()
+		return nil, false
+	}
+
+	s.tick++
+	lvl.used = s.tick
+	s.mu.Unlock()
+
+	return s.root.LevelByIid(iid)
+}
+
+func (s *levelStreamer) Loaded() []Level {
+	s.mu.Lock()
+	iids := make([]string, 0)
+	for _, iid := range s.order {
+		if s.levels[iid].loaded {
+			iids = append(iids, iid)
+		}
+	}
+	s.mu.Unlock()
+
+	levels := make([]Level, 0, len(iids))
+	for _, iid := range iids {
+		if lvl, ok := s.root.LevelByIid(InstanceIdentifier(iid)); ok {
+			levels = append(levels, lvl)
+		}
+	}
+
+	return levels
+}
+
+func (s *levelStreamer) Wait() {
+	s.wg.Wait()
+}
+
+var _ LevelStreamer = &levelStreamer{}
+
+// NewLevelStreamer creates a streamer for the levels of the world w of r. No
+// level is loaded before the first call to Focus. Levels of roots not
+// created by this package are never unloaded from memory, as the streamer
+// cannot release them.
+func NewLevelStreamer(r Root, w World, opts StreamerOptions) LevelStreamer {
+	s := &levelStreamer{
+		root:   r,
+		opts:   opts,
+		levels: make(map[string]*streamedLevel),
+	}
+	if rt, ok := r.(root); ok {
+		s.idx = rt.idx
+	} else {
+		s.idx = newIndex(nil, ".")
+	}
+
+	rects := levelRects(w.Levels(), w.Layout())
+	for i, lvl := range w.Levels() {
+		state := &streamedLevel{
+			iid:        string(lvl.Iid()),
+			bounds:     rects[i],
+			neighbours: make([]string, 0),
+		}
+
+		for _, n := range lvl.Neighbours() {
+			state.neighbours = append(state.neighbours, string(n.LevelIid()))
+		}
+
+		if rel, ok := lvl.ExternalRelPath().Get(); ok && s.idx.sys != nil {
+			if info, err := fs.Stat(s.idx.sys, s.idx.resolve(rel)); err == nil {
+				state.size = info.Size()
+			}
+		}
+
+		s.levels[state.iid] = state
+		s.order = append(s.order, state.iid)
+	}
+
+	return s
+}
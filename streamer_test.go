@@ -0,0 +1,82 @@
+package goldtk
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestLevelStreamer(t *testing.T) {
+	sys := externalLevels()
+	r, err := LoadLazy(sys, "project.ldtk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu       sync.Mutex
+		events   []string
+		unloaded []Level
+	)
+	s := NewLevelStreamer(r, r.Worlds()[0], StreamerOptions{
+		Budget: 1,
+		OnLoad: func(lvl Level) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, "load "+string(lvl.Iid()))
+		},
+		OnUnload: func(lvl Level) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, fmt.Sprintf("unload %s with %d layers", lvl.Iid(), len(lvl.Layers())))
+			unloaded = append(unloaded, lvl)
+		},
+	})
+
+	loaded := func() []InstanceIdentifier {
+		iids := make([]InstanceIdentifier, 0)
+		for _, lvl := range s.Loaded() {
+			iids = append(iids, lvl.Iid())
+		}
+		return iids
+	}
+
+	s.Focus(WorldPoint{X: 8, Y: 8})
+	s.Wait()
+	if got, want := loaded(), []InstanceIdentifier{"level-0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Loaded() around level 0 = %v, want %v", got, want)
+	}
+
+	s.Focus(WorldPoint{X: 40, Y: 8})
+	s.Wait()
+	if got, want := loaded(), []InstanceIdentifier{"level-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Loaded() around level 2 = %v, want %v", got, want)
+	}
+
+	mu.Lock()
+	// Focus unloads the levels left behind before loading the new ones.
+	want := []string{"load level-0", "unload level-0 with 1 layers", "load level-2"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+	for _, lvl := range unloaded {
+		if n := len(lvl.Layers()); n != 1 {
+			t.Errorf("layers of unloaded level %s = %d, want 1", lvl.Iid(), n)
+		}
+	}
+	mu.Unlock()
+
+	if _, ok := r.EntityByIid("missing"); ok {
+		t.Error("EntityByIid() of an unknown iid found an entity")
+	}
+	if got, want := loaded(), []InstanceIdentifier{"level-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Loaded() after a miss = %v, want %v", got, want)
+	}
+
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+	if want := map[string]int{"project/Level_0.ldtkl": 1, "project/Level_2.ldtkl": 1}; !reflect.DeepEqual(sys.reads, want) {
+		t.Errorf("reads = %v, want %v", sys.reads, want)
+	}
+}
@@ -0,0 +1,545 @@
+package tiled
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"goldtk"
+	"image"
+	"image/draw"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Export writes a Tiled map for every level of the project to fsys, along
+// with a tileset for every tileset of the project and for every IntGrid layer.
+//
+// Files are named after identifiers: `<level>.tmx` for levels, `<tileset>.tsx`
+// next to a copy of the tileset image, and `<layer>.intgrid.tsx` for the
+// values of IntGrid layers. Layers become tile layers, IntGrid layers CSV tile
+// layers, and entity layers object groups. Entity and level fields are
+// written as custom properties. Levels of different worlds must not share
+// their identifier.
+func Export(r goldtk.Root, fsys goldtk.WriteFS) error {
+	if err := goldtk.CheckLevelIdentifiers(r); err != nil {
+		return err
+	}
+
+	e := exporter{
+		root:     r,
+		fsys:     fsys,
+		tilesets: make(map[goldtk.Uid]tsxFile),
+		intGrids: make(map[goldtk.Uid]tsxFile),
+	}
+
+	for _, ts := range r.Tilesets() {
+		if err := e.writeTileset(ts); err != nil {
+			return err
+		}
+	}
+
+	for _, lvl := range r.Levels() {
+		if err := e.writeLevel(lvl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tsxFile is a tileset written by the exporter.
+type tsxFile struct {
+	source          string
+	count           int
+	size            int
+	columns         int
+	margin, spacing int
+}
+
+// tileID returns the ID of the tile at the pixel position x, y of the
+// tileset image, if a tile starts there.
+func (f tsxFile) tileID(x, y int) (int, bool) {
+	step := f.size + f.spacing
+	if step <= 0 || (x-f.margin)%step != 0 || (y-f.margin)%step != 0 {
+		return 0, false
+	}
+
+	return (y-f.margin)/step*f.columns + (x-f.margin)/step, true
+}
+
+type exporter struct {
+	root goldtk.Root
+	fsys goldtk.WriteFS
+
+	// tilesets are indexed by tileset UID, and intGrids by layer definition
+	// UID.
+	tilesets map[goldtk.Uid]tsxFile
+	intGrids map[goldtk.Uid]tsxFile
+}
+
+func (e exporter) writeTileset(ts goldtk.Tileset) error {
+	def, ok := e.root.TilesetDefByUid(ts.Uid())
+	if !ok {
+		return fmt.Errorf("tileset %s has no definition", ts.Identifier())
+	}
+
+	name := string(ts.Identifier())
+	if err := goldtk.WriteImage(e.fsys, name+".png", ts.Image()); err != nil {
+		return err
+	}
+
+	tsx := tmxTileset{
+		Version:    tmxVersion,
+		Name:       name,
+		TileWidth:  int(def.TileGridSize),
+		TileHeight: int(def.TileGridSize),
+		Spacing:    int(def.Spacing),
+		Margin:     int(def.Padding),
+		TileCount:  int(def.CWid * def.CHei),
+		Columns:    int(def.CWid),
+		Image: &tmxImage{
+			Source: name + ".png",
+			Width:  int(def.PxWid),
+			Height: int(def.PxHei),
+		},
+	}
+	if len(def.Tags) > 0 {
+		tsx.Properties = tsx.Properties.add("tags", "", strings.Join(def.Tags, ","))
+	}
+
+	tiles := make(map[int]*tmxProperties)
+	for _, data := range def.CustomData {
+		id := int(data.TileID)
+		tiles[id] = tiles[id].add("customData", "", data.Data)
+	}
+
+	// Enum tags become a property named after the enum, listing the values
+	// of the tile.
+	enum := "enumTags"
+	if def.TagsSourceEnumUid != nil {
+		if enumDef, ok := e.root.EnumDefByUid(goldtk.Uid(*def.TagsSourceEnumUid)); ok {
+			enum = enumDef.Identifier
+		}
+	}
+	tags := make(map[int][]string)
+	for _, tag := range def.EnumTags {
+		for _, id := range tag.TileIDS {
+			tags[int(id)] = append(tags[int(id)], tag.EnumValueID)
+		}
+	}
+	for id, values := range tags {
+		tiles[id] = tiles[id].add(enum, "", strings.Join(values, ","))
+	}
+
+	for id, props := range tiles {
+		tsx.Tiles = append(tsx.Tiles, tmxTile{ID: id, Properties: props})
+	}
+	slices.SortFunc(tsx.Tiles, func(a, b tmxTile) int { return a.ID - b.ID })
+
+	if err := writeXML(e.fsys, name+".tsx", tsx); err != nil {
+		return err
+	}
+
+	e.tilesets[ts.Uid()] = tsxFile{
+		source:  name + ".tsx",
+		count:   tsx.TileCount,
+		size:    tsx.TileWidth,
+		columns: tsx.Columns,
+		margin:  tsx.Margin,
+		spacing: tsx.Spacing,
+	}
+
+	return nil
+}
+
+// intGridTileset returns the tileset of the values of an IntGrid layer, made
+// of a square of the color of each value, writing it on first use.
+func (e exporter) intGridTileset(def goldtk.LayerDef) (tsxFile, error) {
+	if f, ok := e.intGrids[def.Uid()]; ok {
+		return f, nil
+	}
+
+	name := string(def.Identifier()) + ".intgrid"
+	size := def.GridSize()
+	values := def.IntGridValues()
+
+	img := image.NewRGBA(image.Rect(0, 0, size*max(1, len(values)), size))
+	tsx := tmxTileset{
+		Version:    tmxVersion,
+		Name:       name,
+		TileWidth:  size,
+		TileHeight: size,
+		TileCount:  len(values),
+		Columns:    len(values),
+		Image: &tmxImage{
+			Source: name + ".png",
+			Width:  img.Bounds().Dx(),
+			Height: size,
+		},
+	}
+
+	for i, v := range values {
+		rect := image.Rect(i*size, 0, (i+1)*size, size)
+		draw.Draw(img, rect, image.NewUniform(v.Color().RGBA()), image.Point{}, draw.Src)
+
+		tile := tmxTile{ID: i, Properties: (*tmxProperties)(nil).add("value", "int", strconv.Itoa(v.Value()))}
+		tile.Type, _ = v.Identifier().Get()
+		tsx.Tiles = append(tsx.Tiles, tile)
+	}
+
+	if err := goldtk.WriteImage(e.fsys, name+".png", img); err != nil {
+		return tsxFile{}, err
+	}
+	if err := writeXML(e.fsys, name+".tsx", tsx); err != nil {
+		return tsxFile{}, err
+	}
+
+	f := tsxFile{source: name + ".tsx", count: len(values), size: size, columns: len(values)}
+	e.intGrids[def.Uid()] = f
+
+	return f, nil
+}
+
+func (e exporter) writeLevel(lvl goldtk.Level) error {
+	size := mapTileSize(lvl)
+
+	b := &mapBuilder{
+		exporter: e,
+		m: tmxMap{
+			Version:         tmxVersion,
+			Orientation:     "orthogonal",
+			RenderOrder:     "right-down",
+			Width:           (lvl.PxWidth() + size - 1) / size,
+			Height:          (lvl.PxHeight() + size - 1) / size,
+			TileWidth:       size,
+			TileHeight:      size,
+			BackgroundColor: lvl.BgColor().Hex(),
+			NextLayerID:     1,
+			NextObjectID:    1,
+		},
+		firstGIDs: make(map[string]uint32),
+		nextGID:   1,
+		objects:   make(map[goldtk.InstanceIdentifier]int),
+	}
+
+	// Object IDs are assigned up front, so that fields can reference any
+	// entity of the level.
+	for _, lyr := range lvl.Layers() {
+		for _, ent := range lyr.Entities() {
+			b.objects[ent.Iid()] = b.m.NextObjectID
+			b.m.NextObjectID++
+		}
+	}
+
+	b.m.Properties = b.m.Properties.add("iid", "", string(lvl.Iid()))
+	b.m.Properties = b.fieldProperties(b.m.Properties, lvl.Fields())
+
+	// Tiled lists layers from the bottom one up.
+	layers := slices.Clone(lvl.Layers())
+	slices.Reverse(layers)
+	for _, lyr := range layers {
+		if err := b.addLayer(lyr); err != nil {
+			return fmt.Errorf("exporting layer %s of level %s: %w", lyr.Identifier(), lvl.Identifier(), err)
+		}
+	}
+
+	return writeXML(e.fsys, string(lvl.Identifier())+".tmx", b.m)
+}
+
+// mapTileSize returns the smallest grid size of the level layers holding
+// tiles or IntGrid values.
+func mapTileSize(lvl goldtk.Level) int {
+	size := 0
+	for _, lyr := range lvl.Layers() {
+		s := lyr.GridSizeInPx()
+		if lyr.Type() != goldtk.EntityLayer && s > 0 && (size == 0 || s < size) {
+			size = s
+		}
+	}
+
+	if size == 0 {
+		return 16
+	}
+
+	return size
+}
+
+// cell is a tile placed on a layer, in layer pixels.
+type cell struct {
+	x, y         int
+	id           int
+	flipX, flipY bool
+}
+
+type mapBuilder struct {
+	exporter
+
+	m         tmxMap
+	firstGIDs map[string]uint32
+	nextGID   uint32
+	objects   map[goldtk.InstanceIdentifier]int
+}
+
+// gid returns the global ID of a tile, adding its tileset to the map on first
+// use.
+func (b *mapBuilder) gid(f tsxFile, c cell) uint32 {
+	first, ok := b.firstGIDs[f.source]
+	if !ok {
+		first = b.nextGID
+		b.firstGIDs[f.source] = first
+		b.nextGID += uint32(max(1, f.count))
+		b.m.Tilesets = append(b.m.Tilesets, tmxTileset{FirstGID: first, Source: f.source})
+	}
+
+	gid := first + uint32(c.id)
+	if c.flipX {
+		gid |= flipX
+	}
+	if c.flipY {
+		gid |= flipY
+	}
+
+	return gid
+}
+
+// layer creates a layer element with the common attributes of lyr.
+func (b *mapBuilder) layer(element, name string, lyr goldtk.Layer) tmxLayer {
+	l := tmxLayer{
+		XMLName: xml.Name{Local: element},
+		ID:      b.m.NextLayerID,
+		Name:    name,
+		OffsetX: float64(lyr.PxTotalOffsetX()),
+		OffsetY: float64(lyr.PxTotalOffsetY()),
+	}
+	b.m.NextLayerID++
+
+	if opacity := float64(lyr.Opacity()); opacity != 1 {
+		l.Opacity = &opacity
+	}
+	if !lyr.IsVisible() {
+		hidden := 0
+		l.Visible = &hidden
+	}
+
+	return l
+}
+
+func (b *mapBuilder) addLayer(lyr goldtk.Layer) error {
+	name := string(lyr.Identifier())
+	tiles := append(lyr.GridTiles(), lyr.AutoLayerTiles()...)
+
+	switch lyr.Type() {
+	case goldtk.EntityLayer:
+		b.addEntities(lyr)
+		return nil
+	case goldtk.IntGridLayer:
+		def := lyr.Def()
+		if def == nil {
+			break
+		}
+
+		f, err := b.intGridTileset(def)
+		if err != nil {
+			return err
+		}
+
+		index := make(map[int]int)
+		for i, v := range def.IntGridValues() {
+			index[v.Value()] = i
+		}
+
+		cells := make([]cell, 0)
+		space := lyr.Space()
+		grid := lyr.IntGrid()
+		for cy := 0; cy < grid.Height(); cy++ {
+			for cx := 0; cx < grid.Width(); cx++ {
+				if id, ok := index[grid.At(cx, cy)]; ok {
+					p := space.GridToLayer(goldtk.GridPoint{CX: cx, CY: cy})
+					cells = append(cells, cell{x: p.X, y: p.Y, id: id})
+				}
+			}
+		}
+		b.addTiles(name, lyr, f, cells)
+
+		// The auto-layer tiles of the IntGrid layer go above its values.
+		name += "_tiles"
+	}
+
+	if len(tiles) == 0 {
+		return nil
+	}
+
+	uid, ok := lyr.TilesetUid().Get()
+	if !ok {
+		return nil
+	}
+	f, ok := b.tilesets[goldtk.Uid(uid)]
+	if !ok {
+		return fmt.Errorf("tileset %d was not exported", uid)
+	}
+
+	cells := make([]cell, len(tiles))
+	for i, t := range tiles {
+		cells[i] = cell{x: t.X(), y: t.Y(), id: t.ID(), flipX: t.FlipX(), flipY: t.FlipY()}
+	}
+	b.addTiles(name, lyr, f, cells)
+
+	return nil
+}
+
+// addTiles adds the cells as tile layers. Tiled holds a single tile per cell,
+// so stacked tiles spill over to additional layers. Layers whose grid does
+// not match the map grid are written as tile objects instead.
+func (b *mapBuilder) addTiles(name string, lyr goldtk.Layer, f tsxFile, cells []cell) {
+	size := lyr.GridSizeInPx()
+
+	if size != b.m.TileWidth {
+		group := b.layer("objectgroup", name, lyr)
+		for _, c := range cells {
+			group.Objects = append(group.Objects, tmxObject{
+				ID:     b.m.NextObjectID,
+				GID:    b.gid(f, c),
+				X:      float64(c.x),
+				Y:      float64(c.y + size),
+				Width:  float64(size),
+				Height: float64(size),
+			})
+			b.m.NextObjectID++
+		}
+		b.m.Layers = append(b.m.Layers, group)
+		return
+	}
+
+	width, height := b.m.Width, b.m.Height
+	stacks := make([][]uint32, 0)
+	depth := make(map[int]int)
+	for _, c := range cells {
+		cx, cy := c.x/size, c.y/size
+		if c.x < 0 || c.y < 0 || cx >= width || cy >= height {
+			continue
+		}
+
+		i := cy*width + cx
+		d := depth[i]
+		depth[i]++
+		if d == len(stacks) {
+			stacks = append(stacks, make([]uint32, width*height))
+		}
+		stacks[d][i] = b.gid(f, c)
+	}
+
+	for d, gids := range stacks {
+		l := b.layer("layer", name, lyr)
+		if d > 0 {
+			l.Name = fmt.Sprintf("%s_%d", name, d+1)
+		}
+		l.Width, l.Height = width, height
+		l.Data = &tmxData{Encoding: "csv", Text: encodeCSV(gids, width)}
+		b.m.Layers = append(b.m.Layers, l)
+	}
+}
+
+func (b *mapBuilder) addEntities(lyr goldtk.Layer) {
+	group := b.layer("objectgroup", string(lyr.Identifier()), lyr)
+
+	for _, ent := range lyr.Entities() {
+		bounds := ent.LayerBounds()
+		obj := tmxObject{
+			ID:     b.objects[ent.Iid()],
+			Name:   string(ent.Identifier()),
+			Type:   string(ent.Identifier()),
+			X:      float64(bounds.X),
+			Y:      float64(bounds.Y),
+			Width:  float64(bounds.W),
+			Height: float64(bounds.H),
+		}
+
+		// Tile objects are anchored at their bottom-left corner.
+		if rect, ok := ent.TileRect().Get(); ok {
+			f, ok := b.tilesets[goldtk.Uid(rect.TilesetUid)]
+			if id, aligned := f.tileID(int(rect.X), int(rect.Y)); ok && aligned && int(rect.W) == f.size && int(rect.H) == f.size {
+				obj.GID = b.gid(f, cell{id: id})
+				obj.Y += obj.Height
+			}
+		}
+
+		obj.Properties = obj.Properties.add("iid", "", string(ent.Iid()))
+		obj.Properties = b.fieldProperties(obj.Properties, ent.Fields())
+
+		group.Objects = append(group.Objects, obj)
+	}
+
+	b.m.Layers = append(b.m.Layers, group)
+}
+
+// fieldProperties appends a property for every non-null field.
+func (b *mapBuilder) fieldProperties(props *tmxProperties, fields []goldtk.Field) *tmxProperties {
+	for _, f := range fields {
+		v := f.Value()
+		if v == nil || v.IsNull() {
+			continue
+		}
+
+		name := string(f.Identifier())
+		typ := f.Type()
+
+		if strings.HasPrefix(typ, "Array<") {
+			data, err := json.Marshal(goldtk.FieldJSON(f))
+			if err == nil {
+				props = props.add(name, "", string(data))
+			}
+			continue
+		}
+
+		switch typ {
+		case "Int":
+			props = props.add(name, "int", strconv.Itoa(v.Int()))
+		case "Float":
+			props = props.add(name, "float", strconv.FormatFloat(v.Float64(), 'g', -1, 64))
+		case "Bool":
+			props = props.add(name, "bool", strconv.FormatBool(v.Bool()))
+		case "Color":
+			// Tiled colors are #AARRGGBB.
+			props = props.add(name, "color", "#ff"+strings.TrimPrefix(goldtk.ColorFromColor(v.Color()).Hex(), "#"))
+		case "FilePath":
+			props = props.add(name, "file", v.FilePath())
+		case "Multilines":
+			props = props.add(name, "", v.Multilines().String())
+		case "EntityRef":
+			// Malformed references decode to nil.
+			ref := v.EntityRef()
+			if ref == nil {
+				continue
+			}
+			if id, ok := b.objects[ref.EntityIid()]; ok {
+				props = props.add(name, "object", strconv.Itoa(id))
+			} else {
+				props = props.add(name, "", string(ref.EntityIid()))
+			}
+		case "Point":
+			p := v.Point()
+			props = props.add(name, "", fmt.Sprintf("%d,%d", p.X, p.Y))
+		case "Tile":
+			// Tile values have no Tiled counterpart.
+		default:
+			props = props.add(name, "", v.String())
+		}
+	}
+
+	return props
+}
+
+func writeXML(fsys goldtk.WriteFS, name string, v any) error {
+	data, err := xml.MarshalIndent(v, "", " ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", name, err)
+	}
+
+	data = append([]byte(xml.Header), data...)
+	if err := fsys.WriteFile(name, append(data, '\n')); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+
+	return nil
+}
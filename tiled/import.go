@@ -0,0 +1,963 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"goldtk"
+	"goldtk/quicktype"
+	"image"
+	"io"
+	"io/fs"
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Import converts the Tiled map name of tmxFS to a project holding a single
+// level.
+//
+// Tile layers become Tiles layers, one per tileset they use, object groups
+// become Entities layers with an entity definition per object class, and
+// custom properties become fields. Every definition gets a fresh UID. Paths
+// in the project, such as tileset images, are relative to the directory of
+// the map; see RebasePaths to save the project elsewhere.
+func Import(tmxFS fs.FS, name string) (quicktype.LdtkJSON, error) {
+	var m tmxMap
+	if err := readXML(tmxFS, name, &m); err != nil {
+		return quicktype.LdtkJSON{}, err
+	}
+
+	if m.Orientation != "" && m.Orientation != "orthogonal" {
+		return quicktype.LdtkJSON{}, fmt.Errorf("importing %s: %s maps are not supported", name, m.Orientation)
+	}
+	if m.Infinite != 0 {
+		return quicktype.LdtkJSON{}, fmt.Errorf("importing %s: infinite maps are not supported", name)
+	}
+	if m.TileWidth != m.TileHeight {
+		return quicktype.LdtkJSON{}, fmt.Errorf("importing %s: tiles of %dx%d are not square", name, m.TileWidth, m.TileHeight)
+	}
+
+	imp := &importer{
+		sys:         tmxFS,
+		dir:         path.Dir(name),
+		m:           m,
+		nextUid:     1,
+		worldIid:    goldtk.NewIid(),
+		identifiers: make(map[string]map[string]bool),
+		fieldIds:    make(map[[2]string]string),
+		entityDefs:  make(map[string]int),
+		objects:     make(map[int]quicktype.ReferenceToAnEntityInstance),
+	}
+
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	if err := imp.importTilesets(); err != nil {
+		return quicktype.LdtkJSON{}, fmt.Errorf("importing %s: %w", name, err)
+	}
+	if err := imp.importLevel(base); err != nil {
+		return quicktype.LdtkJSON{}, fmt.Errorf("importing %s: %w", name, err)
+	}
+
+	return imp.project(), nil
+}
+
+// importedTileset is a Tiled tileset converted to a tileset definition.
+type importedTileset struct {
+	firstGID uint32
+	def      quicktype.TilesetDefinition
+	columns  int
+	margin   int
+	spacing  int
+}
+
+// src returns the pixel position of a tile in the tileset image.
+func (t importedTileset) src(id int) (x, y int) {
+	size := int(t.def.TileGridSize)
+	return t.margin + id%t.columns*(size+t.spacing), t.margin + id/t.columns*(size+t.spacing)
+}
+
+type importer struct {
+	sys fs.FS
+	dir string
+	m   tmxMap
+
+	nextUid  int64
+	worldIid string
+
+	// identifiers holds the identifiers in use, by kind of definition.
+	identifiers map[string]map[string]bool
+
+	// fieldIds maps the scope and name of a property to the identifier of
+	// its field. Fields are scoped to the level, or to an entity definition.
+	fieldIds map[[2]string]string
+
+	tilesets    []importedTileset
+	layerDefs   []quicktype.LayerDefinition
+	entities    []quicktype.EntityDefinition
+	levelFields []quicktype.FieldDefinition
+	levels      []quicktype.Level
+
+	// entityDefs indexes entities by object class, and objects holds the
+	// reference to the entity of every object ID.
+	entityDefs map[string]int
+	objects    map[int]quicktype.ReferenceToAnEntityInstance
+}
+
+func (imp *importer) uid() int64 {
+	uid := imp.nextUid
+	imp.nextUid++
+
+	return uid
+}
+
+// identifier converts a Tiled name to a valid identifier, unique among the
+// definitions of the same kind.
+func (imp *importer) identifier(kind, name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r == '_' || r < 128 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+
+	id := b.String()
+	if id == "" || id[0] >= '0' && id[0] <= '9' {
+		id = "_" + id
+	}
+
+	used := imp.identifiers[kind]
+	if used == nil {
+		used = make(map[string]bool)
+		imp.identifiers[kind] = used
+	}
+
+	unique := id
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", id, i)
+	}
+	used[unique] = true
+
+	return unique
+}
+
+func (imp *importer) importTilesets() error {
+	for _, ref := range imp.m.Tilesets {
+		ts := ref
+		rel := ""
+
+		// External tilesets live in their own file, and their image path is
+		// relative to that file.
+		if ref.Source != "" {
+			if err := readXML(imp.sys, path.Join(imp.dir, ref.Source), &ts); err != nil {
+				return err
+			}
+			rel = path.Dir(ref.Source)
+		}
+
+		if ts.Image == nil {
+			return fmt.Errorf("tileset %s is a collection of images, which is not supported", ts.Name)
+		}
+		if ts.TileWidth != ts.TileHeight {
+			return fmt.Errorf("tileset %s has tiles of %dx%d which are not square", ts.Name, ts.TileWidth, ts.TileHeight)
+		}
+
+		relPath := path.Join(rel, ts.Image.Source)
+		width, height := ts.Image.Width, ts.Image.Height
+		if width == 0 || height == 0 {
+			cfg, err := decodeConfig(imp.sys, path.Join(imp.dir, relPath))
+			if err != nil {
+				return err
+			}
+			width, height = cfg.Width, cfg.Height
+		}
+
+		size := ts.TileWidth
+		columns := ts.Columns
+		if columns == 0 {
+			columns = (width - 2*ts.Margin + ts.Spacing) / (size + ts.Spacing)
+		}
+		rows := (height - 2*ts.Margin + ts.Spacing) / (size + ts.Spacing)
+
+		def := quicktype.TilesetDefinition{
+			CHei:            int64(rows),
+			CWid:            int64(columns),
+			CustomData:      make([]quicktype.TileCustomMetadata, 0),
+			EnumTags:        make([]quicktype.EnumTagValue, 0),
+			Identifier:      imp.identifier("tileset", ts.Name),
+			Padding:         int64(ts.Margin),
+			PxHei:           int64(height),
+			PxWid:           int64(width),
+			RelPath:         &relPath,
+			SavedSelections: make([]map[string]interface{}, 0),
+			Spacing:         int64(ts.Spacing),
+			Tags:            make([]string, 0),
+			TileGridSize:    int64(size),
+			Uid:             imp.uid(),
+		}
+
+		// Tile properties become custom data, as a JSON object unless the
+		// tile only has a customData property, as written by Export.
+		for _, tile := range ts.Tiles {
+			if tile.Properties == nil || len(tile.Properties.Properties) == 0 {
+				continue
+			}
+
+			data := tileData(tile.Properties.Properties)
+			def.CustomData = append(def.CustomData, quicktype.TileCustomMetadata{Data: data, TileID: int64(tile.ID)})
+		}
+
+		imp.tilesets = append(imp.tilesets, importedTileset{
+			firstGID: ref.FirstGID,
+			def:      def,
+			columns:  columns,
+			margin:   ts.Margin,
+			spacing:  ts.Spacing,
+		})
+	}
+
+	slices.SortFunc(imp.tilesets, func(a, b importedTileset) int { return int(a.firstGID) - int(b.firstGID) })
+	return nil
+}
+
+// tileData encodes tile properties as LDtk custom data.
+func tileData(props []tmxProperty) string {
+	if len(props) == 1 && props[0].Name == "customData" {
+		return props[0].value()
+	}
+
+	data := make(map[string]string, len(props))
+	for _, p := range props {
+		data[p.Name] = p.value()
+	}
+
+	b, _ := json.Marshal(data)
+	return string(b)
+}
+
+// tileset returns the tileset holding a global tile ID, and the local ID of
+// the tile.
+func (imp *importer) tileset(gid uint32) (importedTileset, int, bool) {
+	gid &= gidMask
+	for i := len(imp.tilesets) - 1; i >= 0; i-- {
+		if ts := imp.tilesets[i]; ts.firstGID <= gid {
+			return ts, int(gid - ts.firstGID), true
+		}
+	}
+
+	return importedTileset{}, 0, false
+}
+
+func (imp *importer) importLevel(name string) error {
+	size := int64(imp.m.TileWidth)
+	level := quicktype.Level{
+		BgColor:        "#696A79",
+		Neighbours:     make([]quicktype.NeighbourLevel, 0),
+		SmartColor:     "#ADADB5",
+		BgPivotX:       0.5,
+		BgPivotY:       0.5,
+		FieldInstances: make([]quicktype.FieldInstance, 0),
+		Identifier:     imp.identifier("level", name),
+		Iid:            goldtk.NewIid(),
+		LayerInstances: make([]quicktype.LayerInstance, 0),
+		PxHei:          int64(imp.m.Height) * size,
+		PxWid:          int64(imp.m.Width) * size,
+		Uid:            imp.uid(),
+	}
+	if bg, ok := tiledColor(imp.m.BackgroundColor); ok {
+		level.BgColor = bg
+		level.LevelBgColor = &bg
+	}
+
+	layers := flatten(imp.m.Layers, 0, 0)
+
+	// Objects may reference any object of the map, so every object is given
+	// an instance identifier up front. Objects written by Export keep theirs.
+	for i := range layers {
+		if layers[i].XMLName.Local != "objectgroup" {
+			continue
+		}
+
+		layers[i].iid = goldtk.NewIid()
+		for _, obj := range layers[i].Objects {
+			iid := goldtk.NewIid()
+			if p, ok := obj.property("iid"); ok && p.Type == "" {
+				iid = p.value()
+			}
+
+			imp.objects[obj.ID] = quicktype.ReferenceToAnEntityInstance{
+				EntityIid: iid,
+				LayerIid:  layers[i].iid,
+				LevelIid:  level.Iid,
+				WorldIid:  imp.worldIid,
+			}
+		}
+	}
+
+	// LDtk lists layers from the top one down.
+	for i := len(layers) - 1; i >= 0; i-- {
+		lyr := layers[i]
+
+		var insts []quicktype.LayerInstance
+		var err error
+		switch lyr.XMLName.Local {
+		case "layer":
+			insts, err = imp.tileLayers(level, lyr)
+		case "objectgroup":
+			insts = []quicktype.LayerInstance{imp.entityLayer(level, lyr)}
+		}
+		if err != nil {
+			return fmt.Errorf("layer %s: %w", lyr.Name, err)
+		}
+
+		level.LayerInstances = append(level.LayerInstances, insts...)
+	}
+
+	if imp.m.Properties != nil {
+		level.FieldInstances = imp.fields("level field", &imp.levelFields, imp.m.Properties.Properties)
+	}
+
+	imp.levels = append(imp.levels, level)
+	return nil
+}
+
+// flatLayer is a layer out of its groups, with the offset of its groups
+// applied.
+type flatLayer struct {
+	tmxLayer
+	iid string
+}
+
+// flatten lists the layers of groups in place of the groups.
+func flatten(layers []tmxLayer, offX, offY float64) []flatLayer {
+	flat := make([]flatLayer, 0)
+	for _, l := range layers {
+		l.OffsetX += offX
+		l.OffsetY += offY
+
+		switch l.XMLName.Local {
+		case "group":
+			flat = append(flat, flatten(l.Layers, l.OffsetX, l.OffsetY)...)
+		case "layer", "objectgroup":
+			flat = append(flat, flatLayer{tmxLayer: l})
+		}
+	}
+
+	return flat
+}
+
+// layerDef creates the definition of a layer.
+func (imp *importer) layerDef(name string, typ quicktype.Type, lyr flatLayer) quicktype.LayerDefinition {
+	opacity := 1.0
+	if lyr.Opacity != nil {
+		opacity = *lyr.Opacity
+	}
+
+	def := quicktype.LayerDefinition{
+		Type:                   string(typ),
+		AutoRuleGroups:         make([]quicktype.AutoLayerRuleGroup, 0),
+		CanSelectWhenInactive:  true,
+		DisplayOpacity:         opacity,
+		ExcludedTags:           make([]string, 0),
+		GridSize:               int64(imp.m.TileWidth),
+		HideFieldsWhenInactive: true,
+		Identifier:             imp.identifier("layer", name),
+		InactiveOpacity:        0.6,
+		IntGridValues:          make([]quicktype.IntGridValueDefinition, 0),
+		IntGridValuesGroups:    make([]quicktype.IntGridValueGroupDefinition, 0),
+		ParallaxScaling:        true,
+		RenderInWorldView:      true,
+		RequiredTags:           make([]string, 0),
+		LayerDefinitionType:    typ,
+		Uid:                    imp.uid(),
+		UIFilterTags:           make([]string, 0),
+	}
+	imp.layerDefs = append(imp.layerDefs, def)
+
+	return def
+}
+
+// layerInstance creates an empty instance of a layer definition.
+func (imp *importer) layerInstance(level quicktype.Level, def quicktype.LayerDefinition, lyr flatLayer) quicktype.LayerInstance {
+	size := def.GridSize
+	iid := lyr.iid
+	if iid == "" {
+		iid = goldtk.NewIid()
+	}
+
+	offX, offY := int64(math.Round(lyr.OffsetX)), int64(math.Round(lyr.OffsetY))
+
+	return quicktype.LayerInstance{
+		CHei:            (level.PxHei + size - 1) / size,
+		CWid:            (level.PxWid + size - 1) / size,
+		GridSize:        size,
+		Identifier:      def.Identifier,
+		Opacity:         def.DisplayOpacity,
+		PxTotalOffsetX:  offX,
+		PxTotalOffsetY:  offY,
+		Type:            def.Type,
+		AutoLayerTiles:  make([]quicktype.TileInstance, 0),
+		EntityInstances: make([]quicktype.EntityInstance, 0),
+		GridTiles:       make([]quicktype.TileInstance, 0),
+		Iid:             iid,
+		IntGridCSV:      make([]int64, 0),
+		LayerDefUid:     def.Uid,
+		LevelID:         level.Uid,
+		OptionalRules:   make([]int64, 0),
+		PxOffsetX:       offX,
+		PxOffsetY:       offY,
+		Visible:         lyr.Visible == nil || *lyr.Visible != 0,
+	}
+}
+
+// tileLayers converts a tile layer to a Tiles layer for every tileset it
+// uses. Tiles larger than the map grid are anchored at the bottom-left
+// corner of their cell, like Tiled does.
+func (imp *importer) tileLayers(level quicktype.Level, lyr flatLayer) ([]quicktype.LayerInstance, error) {
+	if lyr.Data == nil {
+		return nil, nil
+	}
+
+	gids, err := decodeData(*lyr.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	width := lyr.Width
+	if width == 0 {
+		width = imp.m.Width
+	}
+
+	size := imp.m.TileWidth
+	tiles := make(map[int64][]quicktype.TileInstance)
+	order := make([]importedTileset, 0)
+	for i, gid := range gids {
+		if gid&gidMask == 0 {
+			continue
+		}
+
+		ts, id, ok := imp.tileset(gid)
+		if !ok {
+			return nil, fmt.Errorf("tile %d has no tileset", gid&gidMask)
+		}
+		if _, ok := tiles[ts.def.Uid]; !ok {
+			order = append(order, ts)
+		}
+
+		cx, cy := i%width, i/width
+		tileSize := int(ts.def.TileGridSize)
+		sx, sy := ts.src(id)
+
+		var flip int64
+		if gid&flipX != 0 {
+			flip |= 1
+		}
+		if gid&flipY != 0 {
+			flip |= 2
+		}
+
+		tiles[ts.def.Uid] = append(tiles[ts.def.Uid], quicktype.TileInstance{
+			A:   1,
+			D:   []int64{int64(cy*int(level.PxWid)/size + cx)},
+			F:   flip,
+			Px:  []int64{int64(cx * size), int64((cy+1)*size - tileSize)},
+			Src: []int64{int64(sx), int64(sy)},
+			T:   int64(id),
+		})
+	}
+
+	insts := make([]quicktype.LayerInstance, 0, len(order))
+	for _, ts := range order {
+		name := lyr.Name
+		if len(order) > 1 {
+			name += "_" + ts.def.Identifier
+		}
+
+		def := imp.layerDef(name, quicktype.Tiles, lyr)
+		uid := ts.def.Uid
+		def.TilesetDefUid = &uid
+		imp.layerDefs[len(imp.layerDefs)-1] = def
+
+		inst := imp.layerInstance(level, def, lyr)
+		inst.TilesetDefUid = &uid
+		inst.TilesetRelPath = ts.def.RelPath
+		inst.GridTiles = tiles[uid]
+		insts = append(insts, inst)
+	}
+
+	return insts, nil
+}
+
+// entityLayer converts an object group to an Entities layer.
+func (imp *importer) entityLayer(level quicktype.Level, lyr flatLayer) quicktype.LayerInstance {
+	def := imp.layerDef(lyr.Name, quicktype.Entities, lyr)
+	inst := imp.layerInstance(level, def, lyr)
+	size := float64(def.GridSize)
+
+	for _, obj := range lyr.Objects {
+		entityDef := imp.entityDef(obj)
+
+		width, height := obj.Width, obj.Height
+		if width <= 0 || height <= 0 {
+			width, height = float64(entityDef.Width), float64(entityDef.Height)
+		}
+
+		// Tile objects are anchored at their bottom-left corner.
+		x, y := obj.X, obj.Y
+		if obj.GID != 0 {
+			y -= height
+		}
+
+		px := []int64{int64(math.Round(x)), int64(math.Round(y))}
+		worldX, worldY := px[0]+inst.PxTotalOffsetX, px[1]+inst.PxTotalOffsetY
+
+		ent := quicktype.EntityInstance{
+			Grid:           []int64{int64(math.Floor(x / size)), int64(math.Floor(y / size))},
+			Identifier:     entityDef.Identifier,
+			Pivot:          []float64{0, 0},
+			SmartColor:     entityDef.Color,
+			Tags:           make([]string, 0),
+			Tile:           imp.tileRect(obj.GID),
+			WorldX:         &worldX,
+			WorldY:         &worldY,
+			DefUid:         entityDef.Uid,
+			FieldInstances: make([]quicktype.FieldInstance, 0),
+			Height:         int64(math.Round(height)),
+			Iid:            imp.objects[obj.ID].EntityIid,
+			Px:             px,
+			Width:          int64(math.Round(width)),
+		}
+		if ent.Tile == nil {
+			ent.Tile = entityDef.TileRect
+		}
+		if obj.Properties != nil {
+			props := slices.DeleteFunc(slices.Clone(obj.Properties.Properties), func(p tmxProperty) bool { return p.Name == "iid" })
+			scope := "field of entity " + entityDef.Identifier
+			ent.FieldInstances = imp.fields(scope, &imp.entities[imp.entityDefs[entityDef.Identifier]].FieldDefs, props)
+		}
+
+		inst.EntityInstances = append(inst.EntityInstances, ent)
+	}
+
+	return inst
+}
+
+// entityDef returns the definition of the class of an object, creating it
+// from the object on first use.
+func (imp *importer) entityDef(obj tmxObject) quicktype.EntityDefinition {
+	class := obj.Class
+	if class == "" {
+		class = obj.Type
+	}
+	if class == "" {
+		class = obj.Name
+	}
+	if class == "" {
+		class = "Object"
+	}
+
+	if i, ok := imp.entityDefs[class]; ok {
+		return imp.entities[i]
+	}
+
+	size := int64(imp.m.TileWidth)
+	def := quicktype.EntityDefinition{
+		Color:            "#BE4A2F",
+		FieldDefs:        make([]quicktype.FieldDefinition, 0),
+		FillOpacity:      0.08,
+		Height:           size,
+		Identifier:       imp.identifier("entity", class),
+		LimitBehavior:    quicktype.MoveLastOne,
+		LimitScope:       quicktype.PerLevel,
+		LineOpacity:      1,
+		NineSliceBorders: make([]int64, 0),
+		RenderMode:       quicktype.Rectangle,
+		ResizableX:       true,
+		ResizableY:       true,
+		ShowName:         true,
+		Tags:             make([]string, 0),
+		TileOpacity:      1,
+		TileRenderMode:   quicktype.FitInside,
+		Uid:              imp.uid(),
+		Width:            size,
+	}
+	if obj.Width > 0 && obj.Height > 0 {
+		def.Width, def.Height = int64(math.Round(obj.Width)), int64(math.Round(obj.Height))
+	}
+	if rect := imp.tileRect(obj.GID); rect != nil {
+		def.RenderMode = quicktype.Tile
+		def.TileRect = rect
+		def.TilesetID = &rect.TilesetUid
+		def.LineOpacity = 0
+	}
+
+	// Entity definitions are indexed by class, and by identifier for field
+	// lookups.
+	imp.entityDefs[class] = len(imp.entities)
+	imp.entityDefs[def.Identifier] = len(imp.entities)
+	imp.entities = append(imp.entities, def)
+
+	return def
+}
+
+// tileRect returns the tileset rectangle of a global tile ID.
+func (imp *importer) tileRect(gid uint32) *quicktype.TilesetRectangle {
+	if gid&gidMask == 0 {
+		return nil
+	}
+
+	ts, id, ok := imp.tileset(gid)
+	if !ok {
+		return nil
+	}
+
+	x, y := ts.src(id)
+	return &quicktype.TilesetRectangle{
+		H:          ts.def.TileGridSize,
+		TilesetUid: ts.def.Uid,
+		W:          ts.def.TileGridSize,
+		X:          int64(x),
+		Y:          int64(y),
+	}
+}
+
+// fieldTypes maps Tiled property types to LDtk field types and their
+// internal names.
+var fieldTypes = map[string][2]string{
+	"":       {"String", "F_String"},
+	"string": {"String", "F_String"},
+	"int":    {"Int", "F_Int"},
+	"float":  {"Float", "F_Float"},
+	"bool":   {"Bool", "F_Bool"},
+	"color":  {"Color", "F_Color"},
+	"file":   {"FilePath", "F_Path"},
+	"object": {"EntityRef", "F_EntityRef"},
+}
+
+// fields converts properties to field instances, adding the missing field
+// definitions to defs, whose identifiers are unique within scope. Class
+// properties have no LDtk counterpart, and are left out.
+func (imp *importer) fields(scope string, defs *[]quicktype.FieldDefinition, props []tmxProperty) []quicktype.FieldInstance {
+	insts := make([]quicktype.FieldInstance, 0)
+	for _, p := range props {
+		types, ok := fieldTypes[p.Type]
+		if !ok {
+			continue
+		}
+		if types[0] == "String" && strings.Contains(p.value(), "\n") {
+			types = [2]string{"Multilines", "F_Text"}
+		}
+
+		key := [2]string{scope, p.Name}
+		id, ok := imp.fieldIds[key]
+		if !ok {
+			id = imp.identifier(scope, p.Name)
+			imp.fieldIds[key] = id
+			*defs = append(*defs, imp.fieldDef(id, types))
+		}
+		def := (*defs)[slices.IndexFunc(*defs, func(def quicktype.FieldDefinition) bool { return def.Identifier == id })]
+
+		value, editor := imp.fieldValue(def.Type, p.value())
+		insts = append(insts, quicktype.FieldInstance{
+			Identifier:       def.Identifier,
+			Type:             def.Type,
+			Value:            value,
+			DefUid:           def.Uid,
+			RealEditorValues: editor,
+		})
+	}
+
+	return insts
+}
+
+func (imp *importer) fieldDef(identifier string, types [2]string) quicktype.FieldDefinition {
+	return quicktype.FieldDefinition{
+		Type:                types[0],
+		AllowedRefs:         quicktype.Any,
+		AllowedRefTags:      make([]string, 0),
+		AllowOutOfLevelRef:  true,
+		AutoChainRef:        true,
+		CanBeNull:           true,
+		EditorCutLongValues: true,
+		EditorDisplayMode:   quicktype.Hidden,
+		EditorDisplayPos:    quicktype.Above,
+		EditorDisplayScale:  1,
+		EditorLinkStyle:     quicktype.StraightArrow,
+		EditorShowInWorld:   true,
+		Identifier:          identifier,
+		FieldDefinitionType: types[1],
+		Uid:                 imp.uid(),
+	}
+}
+
+// fieldValue converts a property value to the value of a field of type typ,
+// and to the matching editor values. Values which do not convert are null.
+func (imp *importer) fieldValue(typ, s string) (any, []any) {
+	editor := func(id string, param any) []any {
+		return []any{map[string]any{"id": id, "params": []any{param}}}
+	}
+
+	switch typ {
+	case "Int":
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n, editor("V_Int", n)
+		}
+	case "Float":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, editor("V_Float", f)
+		}
+	case "Bool":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, editor("V_Bool", b)
+		}
+	case "Color":
+		if c, ok := tiledColor(s); ok {
+			n, _ := strconv.ParseInt(c[1:], 16, 64)
+			return c, editor("V_Int", n)
+		}
+	case "EntityRef":
+		id, err := strconv.Atoi(s)
+		if ref, ok := imp.objects[id]; err == nil && ok {
+			return ref, editor("V_String", ref.EntityIid)
+		}
+	default:
+		return s, editor("V_String", s)
+	}
+
+	return nil, make([]any, 0)
+}
+
+// tiledColor converts a Tiled `#AARRGGBB` or `#RRGGBB` color to an LDtk
+// `#RRGGBB` color.
+func tiledColor(s string) (string, bool) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) == 8 {
+		s = s[2:]
+	}
+	if len(s) != 6 {
+		return "", false
+	}
+	if _, err := strconv.ParseUint(s, 16, 32); err != nil {
+		return "", false
+	}
+
+	return "#" + strings.ToUpper(s), true
+}
+
+// project assembles the imported definitions and level.
+func (imp *importer) project() quicktype.LdtkJSON {
+	tilesets := make([]quicktype.TilesetDefinition, len(imp.tilesets))
+	for i, ts := range imp.tilesets {
+		tilesets[i] = ts.def
+	}
+
+	// Every field of an entity gets an instance, null when the object does
+	// not have the property.
+	for li := range imp.levels {
+		for _, lyr := range imp.levels[li].LayerInstances {
+			for ei := range lyr.EntityInstances {
+				ent := &lyr.EntityInstances[ei]
+				def := imp.entities[imp.entityDefs[ent.Identifier]]
+				ent.FieldInstances = completeFields(def.FieldDefs, ent.FieldInstances)
+			}
+		}
+		imp.levels[li].FieldInstances = completeFields(imp.levelFields, imp.levels[li].FieldInstances)
+	}
+
+	size := int64(imp.m.TileWidth)
+	layout := quicktype.WorldLayoutFree
+	gridSize := int64(256)
+	levelWidth, levelHeight := imp.levels[0].PxWid, imp.levels[0].PxHei
+
+	return quicktype.LdtkJSON{
+		AppBuildID:          473703,
+		BackupLimit:         10,
+		BgColor:             "#40465B",
+		CustomCommands:      make([]quicktype.LdtkCustomCommand, 0),
+		DefaultEntityHeight: size,
+		DefaultEntityWidth:  size,
+		DefaultGridSize:     size,
+		DefaultLevelBgColor: "#696A79",
+		DefaultLevelHeight:  &levelHeight,
+		DefaultLevelWidth:   &levelWidth,
+		Defs: quicktype.Definitions{
+			Entities:      imp.entities,
+			Enums:         make([]quicktype.EnumDefinition, 0),
+			ExternalEnums: make([]quicktype.EnumDefinition, 0),
+			Layers:        imp.layerDefs,
+			LevelFields:   imp.levelFields,
+			Tilesets:      tilesets,
+		},
+		DummyWorldIid:    imp.worldIid,
+		ExportLevelBg:    true,
+		Flags:            make([]quicktype.Flag, 0),
+		IdentifierStyle:  quicktype.IdentifierStyleFree,
+		Iid:              goldtk.NewIid(),
+		ImageExportMode:  quicktype.ImageExportModeNone,
+		JSONVersion:      "1.5.3",
+		LevelNamePattern: "Level_%idx",
+		Levels:           imp.levels,
+		NextUid:          imp.nextUid,
+		Toc:              make([]quicktype.LdtkTableOfContentEntry, 0),
+		WorldGridHeight:  &gridSize,
+		WorldGridWidth:   &gridSize,
+		WorldLayout:      &layout,
+		Worlds:           make([]quicktype.World, 0),
+	}
+}
+
+// RebasePaths rewrites the paths of an imported project, relative to the
+// directory of the map, for a project saved elsewhere. dir is the directory of
+// the map relative to the directory of the project, a slash separated path.
+func RebasePaths(project *quicktype.LdtkJSON, dir string) {
+	rebase := func(p *string) *string {
+		if p == nil {
+			return nil
+		}
+
+		rebased := path.Join(dir, *p)
+		return &rebased
+	}
+
+	for i := range project.Defs.Tilesets {
+		project.Defs.Tilesets[i].RelPath = rebase(project.Defs.Tilesets[i].RelPath)
+	}
+
+	for i := range project.Levels {
+		lvl := &project.Levels[i]
+		rebaseFiles(lvl.FieldInstances, dir)
+		for j := range lvl.LayerInstances {
+			lyr := &lvl.LayerInstances[j]
+			lyr.TilesetRelPath = rebase(lyr.TilesetRelPath)
+			for k := range lyr.EntityInstances {
+				rebaseFiles(lyr.EntityInstances[k].FieldInstances, dir)
+			}
+		}
+	}
+}
+
+// rebaseFiles prefixes the values of FilePath fields with dir.
+func rebaseFiles(fields []quicktype.FieldInstance, dir string) {
+	for i := range fields {
+		f := &fields[i]
+		s, ok := f.Value.(string)
+		if f.Type != "FilePath" || !ok {
+			continue
+		}
+
+		f.Value = path.Join(dir, s)
+		f.RealEditorValues = []any{map[string]any{"id": "V_String", "params": []any{f.Value}}}
+	}
+}
+
+// completeFields returns the field instances in definition order, with a null
+// instance for the missing ones.
+func completeFields(defs []quicktype.FieldDefinition, insts []quicktype.FieldInstance) []quicktype.FieldInstance {
+	complete := make([]quicktype.FieldInstance, 0, len(defs))
+	for _, def := range defs {
+		i := slices.IndexFunc(insts, func(inst quicktype.FieldInstance) bool { return inst.DefUid == def.Uid })
+		if i >= 0 {
+			complete = append(complete, insts[i])
+			continue
+		}
+
+		complete = append(complete, quicktype.FieldInstance{
+			Identifier:       def.Identifier,
+			Type:             def.Type,
+			DefUid:           def.Uid,
+			RealEditorValues: make([]any, 0),
+		})
+	}
+
+	return complete
+}
+
+// decodeData returns the global tile IDs of layer data, in any of the Tiled
+// encodings.
+func decodeData(data tmxData) ([]uint32, error) {
+	if len(data.Chunks) > 0 {
+		return nil, fmt.Errorf("chunked layer data is not supported")
+	}
+
+	switch data.Encoding {
+	case "":
+		gids := make([]uint32, len(data.Tiles))
+		for i, t := range data.Tiles {
+			gids[i] = t.GID
+		}
+		return gids, nil
+	case "csv":
+		return decodeCSV(data.Text)
+	case "base64":
+	default:
+		return nil, fmt.Errorf("unknown layer data encoding %s", data.Encoding)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data.Text))
+	if err != nil {
+		return nil, fmt.Errorf("decoding base64 layer data: %w", err)
+	}
+
+	var r io.Reader = bytes.NewReader(raw)
+	switch data.Compression {
+	case "":
+	case "zlib":
+		if r, err = zlib.NewReader(r); err != nil {
+			return nil, fmt.Errorf("decompressing layer data: %w", err)
+		}
+	case "gzip":
+		if r, err = gzip.NewReader(r); err != nil {
+			return nil, fmt.Errorf("decompressing layer data: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("%s layer data compression is not supported", data.Compression)
+	}
+
+	raw, err = io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing layer data: %w", err)
+	}
+
+	gids := make([]uint32, len(raw)/4)
+	for i := range gids {
+		gids[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+
+	return gids, nil
+}
+
+func readXML(sys fs.FS, name string, v any) error {
+	data, err := fs.ReadFile(sys, name)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func decodeConfig(sys fs.FS, name string) (image.Config, error) {
+	f, err := sys.Open(name)
+	if err != nil {
+		return image.Config{}, fmt.Errorf("opening %s: %w", name, err)
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return image.Config{}, fmt.Errorf("decoding %s: %w", name, err)
+	}
+
+	return cfg, nil
+}
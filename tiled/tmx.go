@@ -0,0 +1,220 @@
+// Package tiled converts LDtk projects to and from the TMX map and TSX
+// tileset formats of the Tiled editor.
+package tiled
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Flags stored in the high bits of a global tile ID.
+const (
+	flipX        uint32 = 0x80000000
+	flipY        uint32 = 0x40000000
+	flipDiagonal uint32 = 0x20000000
+	rotatedHex   uint32 = 0x10000000
+	gidMask             = ^(flipX | flipY | flipDiagonal | rotatedHex)
+)
+
+// tmxVersion is the TMX format version written by Export.
+const tmxVersion = "1.8"
+
+type tmxMap struct {
+	XMLName         xml.Name       `xml:"map"`
+	Version         string         `xml:"version,attr"`
+	Orientation     string         `xml:"orientation,attr"`
+	RenderOrder     string         `xml:"renderorder,attr,omitempty"`
+	Width           int            `xml:"width,attr"`
+	Height          int            `xml:"height,attr"`
+	TileWidth       int            `xml:"tilewidth,attr"`
+	TileHeight      int            `xml:"tileheight,attr"`
+	Infinite        int            `xml:"infinite,attr"`
+	BackgroundColor string         `xml:"backgroundcolor,attr,omitempty"`
+	NextLayerID     int            `xml:"nextlayerid,attr"`
+	NextObjectID    int            `xml:"nextobjectid,attr"`
+	Properties      *tmxProperties `xml:"properties"`
+	Tilesets        []tmxTileset   `xml:"tileset"`
+
+	// Layers holds the layer, objectgroup, imagelayer and group elements,
+	// from the bottom one up.
+	Layers []tmxLayer `xml:",any"`
+}
+
+// tmxLayer is a `layer`, `objectgroup`, `imagelayer` or `group` element,
+// told apart by its XMLName.
+type tmxLayer struct {
+	XMLName    xml.Name
+	ID         int            `xml:"id,attr"`
+	Name       string         `xml:"name,attr"`
+	Class      string         `xml:"class,attr,omitempty"`
+	Width      int            `xml:"width,attr,omitempty"`
+	Height     int            `xml:"height,attr,omitempty"`
+	Opacity    *float64       `xml:"opacity,attr"`
+	Visible    *int           `xml:"visible,attr"`
+	OffsetX    float64        `xml:"offsetx,attr,omitempty"`
+	OffsetY    float64        `xml:"offsety,attr,omitempty"`
+	Properties *tmxProperties `xml:"properties"`
+	Data       *tmxData       `xml:"data"`
+	Objects    []tmxObject    `xml:"object"`
+
+	// Layers holds the children of a group.
+	Layers []tmxLayer `xml:",any"`
+}
+
+type tmxData struct {
+	Encoding    string        `xml:"encoding,attr,omitempty"`
+	Compression string        `xml:"compression,attr,omitempty"`
+	Tiles       []tmxDataTile `xml:"tile"`
+	Chunks      []tmxChunk    `xml:"chunk"`
+
+	// Text holds the CSV or base64 data. It is kept as inner XML so that
+	// CSV rows stay on their own lines.
+	Text string `xml:",innerxml"`
+}
+
+type tmxDataTile struct {
+	GID uint32 `xml:"gid,attr"`
+}
+
+type tmxChunk struct {
+	X      int    `xml:"x,attr"`
+	Y      int    `xml:"y,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+	Text   string `xml:",chardata"`
+}
+
+type tmxObject struct {
+	ID         int            `xml:"id,attr"`
+	Name       string         `xml:"name,attr,omitempty"`
+	Type       string         `xml:"type,attr,omitempty"`
+	Class      string         `xml:"class,attr,omitempty"`
+	GID        uint32         `xml:"gid,attr,omitempty"`
+	X          float64        `xml:"x,attr"`
+	Y          float64        `xml:"y,attr"`
+	Width      float64        `xml:"width,attr,omitempty"`
+	Height     float64        `xml:"height,attr,omitempty"`
+	Rotation   float64        `xml:"rotation,attr,omitempty"`
+	Visible    *int           `xml:"visible,attr"`
+	Properties *tmxProperties `xml:"properties"`
+	Point      *struct{}      `xml:"point"`
+}
+
+type tmxProperties struct {
+	Properties []tmxProperty `xml:"property"`
+}
+
+type tmxProperty struct {
+	Name         string `xml:"name,attr"`
+	Type         string `xml:"type,attr,omitempty"`
+	PropertyType string `xml:"propertytype,attr,omitempty"`
+	Value        string `xml:"value,attr"`
+
+	// Text holds multiline string values, which Tiled writes as the element
+	// content instead of the value attribute.
+	Text string `xml:",chardata"`
+}
+
+// tmxTileset is a tileset, either a reference from a map to a TSX file, a
+// tileset embedded in a map, or the root of a TSX file.
+type tmxTileset struct {
+	XMLName    xml.Name       `xml:"tileset"`
+	FirstGID   uint32         `xml:"firstgid,attr,omitempty"`
+	Source     string         `xml:"source,attr,omitempty"`
+	Version    string         `xml:"version,attr,omitempty"`
+	Name       string         `xml:"name,attr,omitempty"`
+	TileWidth  int            `xml:"tilewidth,attr,omitempty"`
+	TileHeight int            `xml:"tileheight,attr,omitempty"`
+	Spacing    int            `xml:"spacing,attr,omitempty"`
+	Margin     int            `xml:"margin,attr,omitempty"`
+	TileCount  int            `xml:"tilecount,attr,omitempty"`
+	Columns    int            `xml:"columns,attr,omitempty"`
+	Properties *tmxProperties `xml:"properties"`
+	Image      *tmxImage      `xml:"image"`
+	Tiles      []tmxTile      `xml:"tile"`
+}
+
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr,omitempty"`
+	Height int    `xml:"height,attr,omitempty"`
+}
+
+type tmxTile struct {
+	ID         int            `xml:"id,attr"`
+	Type       string         `xml:"type,attr,omitempty"`
+	Class      string         `xml:"class,attr,omitempty"`
+	Properties *tmxProperties `xml:"properties"`
+	Image      *tmxImage      `xml:"image"`
+}
+
+// add appends a property, creating the list when needed.
+func (p *tmxProperties) add(name, typ, value string) *tmxProperties {
+	if p == nil {
+		p = &tmxProperties{}
+	}
+
+	p.Properties = append(p.Properties, tmxProperty{Name: name, Type: typ, Value: value})
+	return p
+}
+
+// value returns the value of a property, from its attribute or its content.
+func (p tmxProperty) value() string {
+	if p.Value == "" {
+		return p.Text
+	}
+
+	return p.Value
+}
+
+// property returns the property of an object by name.
+func (o tmxObject) property(name string) (tmxProperty, bool) {
+	if o.Properties == nil {
+		return tmxProperty{}, false
+	}
+
+	for _, p := range o.Properties.Properties {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return tmxProperty{}, false
+}
+
+// encodeCSV writes global tile IDs the way Tiled does, one row per line.
+func encodeCSV(gids []uint32, width int) string {
+	var b strings.Builder
+	b.WriteString("\n")
+	for i, gid := range gids {
+		b.WriteString(strconv.FormatUint(uint64(gid), 10))
+		if i < len(gids)-1 {
+			b.WriteString(",")
+		}
+		if (i+1)%width == 0 {
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// decodeCSV reads global tile IDs from CSV layer data.
+func decodeCSV(text string) ([]uint32, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r' || r == ' ' || r == '\t'
+	})
+
+	gids := make([]uint32, len(fields))
+	for i, f := range fields {
+		gid, err := strconv.ParseUint(f, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("decoding csv: %w", err)
+		}
+		gids[i] = uint32(gid)
+	}
+
+	return gids, nil
+}
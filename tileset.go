@@ -129,22 +129,15 @@ func NewTileset(def quicktype.TilesetDefinition, sys fs.FS) (Tileset, error) {
 		return nil, fmt.Errorf("tileset definition requires relative path")
 	}
 
-	// Open the tileset image file
-	f, err := sys.Open(*def.RelPath)
-	if err != nil {
-		return nil, fmt.Errorf("opening tileset %s: %w", *def.RelPath, err)
-	}
-	defer func(f fs.File) {
-		err := f.Close()
-		if err != nil {
-			log.Fatalf("failed to close tileset file: %v", err)
-		}
-	}(f)
-
-	// Decode the image
-	src, _, err := image.Decode(f)
+	return openTileset(def, sys, *def.RelPath)
+}
+
+// openTileset creates a Tileset from the image file name of sys, decoded
+// through the process-wide image cache.
+func openTileset(def quicktype.TilesetDefinition, sys fs.FS, name string) (Tileset, error) {
+	src, err := images.decode(sys, name)
 	if err != nil {
-		return nil, fmt.Errorf("decoding tileset image %s: %w", *def.RelPath, err)
+		return nil, err
 	}
 
 	return tileset{
@@ -158,11 +151,17 @@ var _ Tileset = tileset{}
 
 // Tile interface defines methods for working with individual tiles, including their properties and image representation.
 type Tile interface {
+	// ID returns the tile ID in the tileset.
+	ID() int
+
 	Opacity() float64
 
 	X() int
 	Y() int
 
+	// Src returns the pixel coordinates of the tile in the tileset image.
+	Src() (x, y int)
+
 	FlipY() bool
 	FlipX() bool
 	FlipBoth() bool
@@ -176,6 +175,11 @@ type tile struct {
 	ts   Tileset
 }
 
+// ID returns the tile ID in the tileset.
+func (t tile) ID() int {
+	return int(t.inst.T)
+}
+
 // Opacity returns the opacity of the tile.
 func (t tile) Opacity() float64 {
 	return t.inst.A
@@ -191,6 +195,15 @@ func (t tile) Y() int {
 	return int(t.inst.Px[1])
 }
 
+// Src returns the pixel coordinates of the tile in the tileset image.
+func (t tile) Src() (x, y int) {
+	if len(t.inst.Src) < 2 {
+		return 0, 0
+	}
+
+	return int(t.inst.Src[0]), int(t.inst.Src[1])
+}
+
 // FlipX returns true if the tile is flipped along the X axis.
 func (t tile) FlipX() bool {
 	return t.inst.F&1 != 0
@@ -207,7 +220,12 @@ func (t tile) FlipBoth() bool {
 }
 
 // Image returns the image of the tile, extracted from the associated tileset.
+// It returns nil when the tileset was not loaded.
 func (t tile) Image() image.Image {
+	if t.ts == nil {
+		return nil
+	}
+
 	return t.ts.Tile(int(t.inst.T))
 }
 
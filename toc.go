@@ -0,0 +1,177 @@
+package goldtk
+
+import (
+	"encoding/json"
+	"fmt"
+	"goldtk/quicktype"
+	"io/fs"
+	"math"
+	"path"
+	"slices"
+)
+
+// TOCEntry is an entity listed in the table of contents of a project, which
+// holds the entities whose definition has the `exportToToc` option set.
+type TOCEntry interface {
+	Identifier() Identifier
+
+	// Reference points to the entity, along with its layer, level and world.
+	Reference() Reference
+
+	// WorldBounds is the area covered by the entity in the world.
+	WorldBounds() WorldRect
+
+	// Fields returns the fields exported to the table of contents, in the
+	// order of the entity definition.
+	Fields() []Field
+}
+
+type tocEntry struct {
+	id   string
+	inst quicktype.LdtkTocInstanceData
+	def  *quicktype.EntityDefinition
+	idx  *index
+}
+
+func (e tocEntry) Identifier() Identifier {
+	return Identifier(e.id)
+}
+
+func (e tocEntry) Reference() Reference {
+	return reference{inst: e.inst.Iids, idx: e.idx}
+}
+
+// WorldBounds moves the entity position, the one of its pivot, to the
+// top-left corner of the entity.
+func (e tocEntry) WorldBounds() WorldRect {
+	var pivotX, pivotY float64
+	if e.def != nil {
+		pivotX, pivotY = e.def.PivotX, e.def.PivotY
+	}
+
+	return WorldRect{
+		X: int(e.inst.WorldX) - int(math.Round(pivotX*float64(e.inst.WidPx))),
+		Y: int(e.inst.WorldY) - int(math.Round(pivotY*float64(e.inst.HeiPx))),
+		W: int(e.inst.WidPx),
+		H: int(e.inst.HeiPx),
+	}
+}
+
+// Fields types the values of the entry with the entity definition. Values
+// without definition keep their JSON type, and come last by identifier.
+func (e tocEntry) Fields() []Field {
+	values, _ := e.inst.Fields.(map[string]any)
+
+	fields := make([]Field, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	if e.def != nil {
+		for _, def := range e.def.FieldDefs {
+			raw, ok := values[def.Identifier]
+			if !ok {
+				continue
+			}
+
+			seen[def.Identifier] = true
+			fields = append(fields, newField(quicktype.FieldInstance{
+				Identifier: def.Identifier,
+				Type:       def.Type,
+				Value:      raw,
+				DefUid:     def.Uid,
+			}, e.idx))
+		}
+	}
+
+	rest := make([]string, 0)
+	for id := range values {
+		if !seen[id] {
+			rest = append(rest, id)
+		}
+	}
+	slices.Sort(rest)
+
+	for _, id := range rest {
+		fields = append(fields, newField(quicktype.FieldInstance{Identifier: id, Value: values[id]}, e.idx))
+	}
+
+	return fields
+}
+
+var _ TOCEntry = tocEntry{}
+
+// newTOC lists the entries of a table of contents. Entries of projects saved
+// before 1.4 only hold references, and have no position nor fields.
+func newTOC(toc []quicktype.LdtkTableOfContentEntry, idx *index) []TOCEntry {
+	entries := make([]TOCEntry, 0)
+	for _, group := range toc {
+		var def *quicktype.EntityDefinition
+		for _, d := range idx.entityDefs {
+			if d.Identifier == group.Identifier {
+				def = &d
+				break
+			}
+		}
+
+		for _, inst := range group.InstancesData {
+			entries = append(entries, tocEntry{id: group.Identifier, inst: inst, def: def, idx: idx})
+		}
+		if len(group.InstancesData) == 0 {
+			for _, ref := range group.Instances {
+				inst := quicktype.LdtkTocInstanceData{Iids: ref}
+				entries = append(entries, tocEntry{id: group.Identifier, inst: inst, def: def, idx: idx})
+			}
+		}
+	}
+
+	return entries
+}
+
+// LoadTOC reads the table of contents of the project file name from sys,
+// without decoding its levels. References of the entries do not resolve to
+// instances.
+func LoadTOC(sys fs.FS, name string) ([]TOCEntry, error) {
+	f, err := sys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading project %s: %w", name, err)
+	}
+	defer f.Close()
+
+	// The project is read a token at a time like a LevelDecoder, until the
+	// definitions and the table of contents are decoded. The editor writes
+	// both before the levels, which are then never read; other members are
+	// skipped over.
+	d := &levelDecoder{dec: json.NewDecoder(f)}
+	if err := d.delim('{'); err != nil {
+		return nil, fmt.Errorf("reading project %s: %w", name, err)
+	}
+
+	var (
+		defs            quicktype.Definitions
+		toc             []quicktype.LdtkTableOfContentEntry
+		hasDefs, hasTOC bool
+	)
+	for (!hasDefs || !hasTOC) && d.dec.More() {
+		key, err := d.key()
+		if err != nil {
+			return nil, fmt.Errorf("reading project %s: %w", name, err)
+		}
+
+		switch key {
+		case "defs":
+			hasDefs = true
+			err = d.dec.Decode(&defs)
+		case "toc":
+			hasTOC = true
+			err = d.dec.Decode(&toc)
+		default:
+			err = d.skip()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading project %s: %s: %w", name, key, err)
+		}
+	}
+
+	idx := newIndex(sys, path.Dir(name))
+	idx.addDefs(defs)
+
+	return newTOC(toc, idx), nil
+}
@@ -0,0 +1,138 @@
+package goldtk
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// schemaFS holds the official LDtk JSON schema, downloaded with `task schema`
+// as schema/ldtk.schema.json, see schema/README.md.
+//
+//go:embed schema
+var schemaFS embed.FS
+
+const schemaFile = "schema/ldtk.schema.json"
+
+// ValidationError is a violation of the LDtk JSON schema.
+type ValidationError struct {
+	// Pointer is the JSON pointer (RFC 6901) of the offending node. The empty
+	// string points to the whole document.
+	Pointer string
+
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pointer, e.Message)
+}
+
+var schemas struct {
+	once    sync.Once
+	project *gojsonschema.Schema
+	level   *gojsonschema.Schema
+	err     error
+}
+
+// ValidateSchema checks a project file, or a `.ldtkl` level file, against the
+// LDtk JSON schema. It returns no errors when the document is valid, and a
+// single error when the module was built without the schema.
+func ValidateSchema(data []byte) []ValidationError {
+	schemas.once.Do(compileSchemas)
+	if schemas.err != nil {
+		return []ValidationError{{Message: fmt.Sprintf("compiling schema: %v", schemas.err)}}
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []ValidationError{{Message: fmt.Sprintf("decoding document: %v", err)}}
+	}
+
+	schema := schemas.project
+	if isLevelDocument(doc) {
+		schema = schemas.level
+	}
+
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return []ValidationError{{Message: fmt.Sprintf("validating document: %v", err)}}
+	}
+
+	errs := make([]ValidationError, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		errs = append(errs, ValidationError{
+			Pointer: jsonPointer(e.Context()),
+			Message: e.Description(),
+		})
+	}
+
+	return errs
+}
+
+// isLevelDocument tells `.ldtkl` files apart from projects, which always hold
+// definitions.
+func isLevelDocument(doc map[string]json.RawMessage) bool {
+	_, hasDefs := doc["defs"]
+	_, hasLayers := doc["layerInstances"]
+
+	return !hasDefs && hasLayers
+}
+
+// compileSchemas builds a schema for projects, and one for level files which
+// only differs by its root reference.
+func compileSchemas() {
+	data, err := schemaFS.ReadFile(schemaFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		schemas.err = fmt.Errorf("%s is missing, run `task schema` and rebuild", schemaFile)
+		return
+	}
+	if err != nil {
+		schemas.err = err
+		return
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		schemas.err = err
+		return
+	}
+
+	compile := func(root string) (*gojsonschema.Schema, error) {
+		doc["$ref"] = root
+
+		loader := gojsonschema.NewSchemaLoader()
+		loader.Draft = gojsonschema.Draft7
+		loader.AutoDetect = false
+
+		return loader.Compile(gojsonschema.NewGoLoader(doc))
+	}
+
+	schemas.project, schemas.err = compile("#/LdtkJsonRoot")
+	if schemas.err != nil {
+		return
+	}
+	schemas.level, schemas.err = compile("#/otherTypes/Level")
+}
+
+// jsonPointer converts a validation context, such as `(root).levels.0`, to a
+// JSON pointer.
+func jsonPointer(ctx *gojsonschema.JsonContext) string {
+	const sep = "\x00"
+
+	parts := strings.Split(ctx.String(sep), sep)
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+
+	var b strings.Builder
+	for _, part := range parts[1:] {
+		b.WriteString("/")
+		b.WriteString(escaper.Replace(part))
+	}
+
+	return b.String()
+}
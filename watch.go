@@ -0,0 +1,260 @@
+package goldtk
+
+import (
+	"context"
+	"fmt"
+	"goldtk/quicktype"
+	"io/fs"
+	"path"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// WatchInterval is the time between two checks of the files of a watched
+// project.
+var WatchInterval = 500 * time.Millisecond
+
+// Change summarizes what changed between two versions of a project.
+type Change struct {
+	LevelsAdded    []InstanceIdentifier
+	LevelsRemoved  []InstanceIdentifier
+	LevelsModified []InstanceIdentifier
+
+	// TilesetsChanged lists the tilesets whose definition or image changed,
+	// or which were added or removed.
+	TilesetsChanged []Uid
+}
+
+// IsEmpty returns true when nothing changed.
+func (c Change) IsEmpty() bool {
+	return len(c.LevelsAdded) == 0 && len(c.LevelsRemoved) == 0 && len(c.LevelsModified) == 0 && len(c.TilesetsChanged) == 0
+}
+
+// fileStamp is the modification info of a file, zero for missing files.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+func stamp(sys fs.FS, name string) fileStamp {
+	info, err := fs.Stat(sys, name)
+	if err != nil {
+		return fileStamp{}
+	}
+
+	return fileStamp{size: info.Size(), modTime: info.ModTime()}
+}
+
+// watchedProject is a version of a watched project, along with the
+// modification info of its files.
+type watchedProject struct {
+	ldtk    quicktype.LdtkJSON
+	decoded bool
+	project fileStamp
+	levels  map[string]fileStamp
+	images  map[int64]fileStamp
+}
+
+// Watch loads the project file name of sys, and checks its files every
+// WatchInterval until ctx is done: the project file, the external level files
+// and the tileset images. onChange is first called with the loaded project,
+// every level being added, then with a new Root whenever files change.
+//
+// Only the changed files are read again: the project file when it changed,
+// and the tileset images through the image cache. Reload failures, such as
+// files caught while being saved, are given to onError when it is not nil,
+// and the files are read again once one of them changes. Watch returns the
+// error of the first load, or the error of ctx.
+func Watch(ctx context.Context, sys fs.FS, name string, onChange func(Root, Change), onError func(error)) error {
+	dir := path.Dir(name)
+
+	current, err := readWatched(sys, name, nil)
+	if err != nil {
+		return err
+	}
+
+	r, err := newRoot(current.ldtk, sys, dir, false)
+	if err != nil {
+		return err
+	}
+	onChange(r, diffWatched(nil, current))
+
+	ticker := time.NewTicker(WatchInterval)
+	defer ticker.Stop()
+
+	// failed holds the files of the version which failed to load, so that
+	// they are only read again once saved again. Its project is decoded when
+	// the project file could be read.
+	var failed *watchedProject
+
+	fail := func(w *watchedProject, err error) {
+		failed = w
+		if onError != nil {
+			onError(fmt.Errorf("reloading project %s: %w", name, err))
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		prev := current
+		if failed != nil {
+			if !failed.changed(sys, name) {
+				continue
+			}
+			if failed.decoded {
+				prev = failed
+			}
+		}
+
+		next, err := readWatched(sys, name, prev)
+		if err != nil {
+			fail(&watchedProject{project: stamp(sys, name)}, err)
+			continue
+		}
+
+		change := diffWatched(current, next)
+		if change.IsEmpty() {
+			current, failed = next, nil
+			continue
+		}
+
+		r, err := newRoot(next.ldtk, sys, dir, false)
+		if err != nil {
+			fail(next, err)
+			continue
+		}
+
+		current, failed = next, nil
+		onChange(r, change)
+	}
+}
+
+// readWatched stamps the files of the project, and decodes the project file
+// when it changed since prev.
+func readWatched(sys fs.FS, name string, prev *watchedProject) (*watchedProject, error) {
+	w := &watchedProject{project: stamp(sys, name), decoded: true}
+
+	if prev != nil && prev.project == w.project {
+		w.ldtk = prev.ldtk
+	} else {
+		data, err := fs.ReadFile(sys, name)
+		if err != nil {
+			return nil, fmt.Errorf("reading project %s: %w", name, err)
+		}
+
+		if w.ldtk, err = quicktype.UnmarshalLdtkJSON(data); err != nil {
+			return nil, fmt.Errorf("decoding project %s: %w", name, err)
+		}
+	}
+
+	dir := path.Dir(name)
+	w.levels = make(map[string]fileStamp)
+	for _, lvl := range projectLevels(w.ldtk) {
+		if lvl.ExternalRelPath != nil {
+			w.levels[lvl.Iid] = stamp(sys, path.Join(dir, *lvl.ExternalRelPath))
+		}
+	}
+
+	w.images = make(map[int64]fileStamp)
+	for _, def := range w.ldtk.Defs.Tilesets {
+		if def.RelPath != nil && def.EmbedAtlas == nil {
+			w.images[def.Uid] = stamp(sys, path.Join(dir, *def.RelPath))
+		}
+	}
+
+	return w, nil
+}
+
+// changed returns true when a file of w was modified since it was stamped.
+func (w *watchedProject) changed(sys fs.FS, name string) bool {
+	if stamp(sys, name) != w.project {
+		return true
+	}
+
+	dir := path.Dir(name)
+	for _, lvl := range projectLevels(w.ldtk) {
+		if lvl.ExternalRelPath != nil && stamp(sys, path.Join(dir, *lvl.ExternalRelPath)) != w.levels[lvl.Iid] {
+			return true
+		}
+	}
+	for _, def := range w.ldtk.Defs.Tilesets {
+		if def.RelPath != nil && def.EmbedAtlas == nil && stamp(sys, path.Join(dir, *def.RelPath)) != w.images[def.Uid] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diffWatched summarizes the changes from prev to next. Every level and
+// tileset of next is added when prev is nil.
+func diffWatched(prev, next *watchedProject) Change {
+	change := Change{
+		LevelsAdded:     make([]InstanceIdentifier, 0),
+		LevelsRemoved:   make([]InstanceIdentifier, 0),
+		LevelsModified:  make([]InstanceIdentifier, 0),
+		TilesetsChanged: make([]Uid, 0),
+	}
+
+	before := make(map[string]quicktype.Level)
+	beforeDefs := make(map[int64]quicktype.TilesetDefinition)
+	if prev != nil {
+		for _, lvl := range projectLevels(prev.ldtk) {
+			before[lvl.Iid] = lvl
+		}
+		for _, def := range prev.ldtk.Defs.Tilesets {
+			beforeDefs[def.Uid] = def
+		}
+	}
+
+	for _, lvl := range projectLevels(next.ldtk) {
+		old, ok := before[lvl.Iid]
+		delete(before, lvl.Iid)
+
+		switch {
+		case !ok:
+			change.LevelsAdded = append(change.LevelsAdded, InstanceIdentifier(lvl.Iid))
+		case !reflect.DeepEqual(old, lvl) || prev.levels[lvl.Iid] != next.levels[lvl.Iid]:
+			change.LevelsModified = append(change.LevelsModified, InstanceIdentifier(lvl.Iid))
+		}
+	}
+	for iid := range before {
+		change.LevelsRemoved = append(change.LevelsRemoved, InstanceIdentifier(iid))
+	}
+	slices.Sort(change.LevelsRemoved)
+
+	for _, def := range next.ldtk.Defs.Tilesets {
+		old, ok := beforeDefs[def.Uid]
+		delete(beforeDefs, def.Uid)
+
+		if !ok || !reflect.DeepEqual(old, def) || prev.images[def.Uid] != next.images[def.Uid] {
+			change.TilesetsChanged = append(change.TilesetsChanged, Uid(def.Uid))
+		}
+	}
+	for uid := range beforeDefs {
+		change.TilesetsChanged = append(change.TilesetsChanged, Uid(uid))
+	}
+	slices.Sort(change.TilesetsChanged)
+
+	return change
+}
+
+// projectLevels returns the levels of every world of a project.
+func projectLevels(ldtk quicktype.LdtkJSON) []quicktype.Level {
+	if len(ldtk.Worlds) == 0 {
+		return ldtk.Levels
+	}
+
+	levels := make([]quicktype.Level, 0)
+	for _, w := range ldtk.Worlds {
+		levels = append(levels, w.Levels...)
+	}
+
+	return levels
+}
@@ -0,0 +1,160 @@
+package goldtk
+
+import (
+	"goldtk/quicktype"
+)
+
+// WorldLayout describes how the levels of a world are organized.
+type WorldLayout string
+
+const (
+	WorldLayoutFree             WorldLayout = "Free"
+	WorldLayoutGridVania        WorldLayout = "GridVania"
+	WorldLayoutLinearHorizontal WorldLayout = "LinearHorizontal"
+	WorldLayoutLinearVertical   WorldLayout = "LinearVertical"
+)
+
+// World is a collection of levels sharing a single layout and coordinate space.
+type World interface {
+	Identifier() Identifier
+	Iid() InstanceIdentifier
+
+	Layout() WorldLayout
+
+	GridWidth() int
+	GridHeight() int
+
+	Levels() []Level
+
+	// LevelAt returns the level at the given depth which contains the world
+	// pixel coordinate x, y. Levels of LinearHorizontal and LinearVertical
+	// worlds are saved at -1, -1: they are placed side by side in order,
+	// starting at 0, 0.
+	LevelAt(x, y, depth int) (Level, bool)
+
+	// LevelsIn returns every level, of any depth, overlapping the rectangle.
+	LevelsIn(rect WorldRect) []Level
+}
+
+type world struct {
+	inst   quicktype.World
+	levels []Level
+	rects  []WorldRect
+	index  levelIndex
+}
+
+func (w world) Identifier() Identifier {
+	return Identifier(w.inst.Identifier)
+}
+
+func (w world) Iid() InstanceIdentifier {
+	return InstanceIdentifier(w.inst.Iid)
+}
+
+func (w world) Layout() WorldLayout {
+	if w.inst.WorldLayout == nil {
+		return WorldLayoutFree
+	}
+
+	return WorldLayout(*w.inst.WorldLayout)
+}
+
+func (w world) GridWidth() int {
+	return int(w.inst.WorldGridWidth)
+}
+
+func (w world) GridHeight() int {
+	return int(w.inst.WorldGridHeight)
+}
+
+func (w world) Levels() []Level {
+	return w.levels
+}
+
+func (w world) LevelAt(x, y, depth int) (Level, bool) {
+	for _, i := range w.index.at(x, y) {
+		lvl := w.levels[i]
+		if lvl.WorldDepth() == depth && w.rects[i].Contains(x, y) {
+			return lvl, true
+		}
+	}
+
+	return nil, false
+}
+
+func (w world) LevelsIn(rect WorldRect) []Level {
+	levels := make([]Level, 0)
+	for _, i := range w.index.in(rect) {
+		if w.rects[i].Overlaps(rect) {
+			levels = append(levels, w.levels[i])
+		}
+	}
+
+	return levels
+}
+
+func NewWorld(inst quicktype.World) World {
+	return newWorld(inst, nil)
+}
+
+func newWorld(inst quicktype.World, idx *index) World {
+	levels := make([]Level, len(inst.Levels))
+	for i, l := range inst.Levels {
+		levels[i] = newLevel(l, idx)
+	}
+
+	w := world{inst: inst, levels: levels}
+	w.rects = levelRects(levels, w.Layout())
+	w.index = newLevelIndex(w.rects, int(inst.WorldGridWidth), int(inst.WorldGridHeight))
+
+	return w
+}
+
+var _ World = world{}
+
+// implicitWorld builds the single world of a project which has not enabled
+// the Multi-worlds option, where levels and layout settings live in the root.
+func implicitWorld(ldtk quicktype.LdtkJSON) quicktype.World {
+	w := quicktype.World{
+		Identifier:  "World",
+		Iid:         ldtk.DummyWorldIid,
+		Levels:      ldtk.Levels,
+		WorldLayout: ldtk.WorldLayout,
+	}
+
+	if ldtk.WorldGridWidth != nil {
+		w.WorldGridWidth = *ldtk.WorldGridWidth
+	}
+	if ldtk.WorldGridHeight != nil {
+		w.WorldGridHeight = *ldtk.WorldGridHeight
+	}
+	if ldtk.DefaultLevelWidth != nil {
+		w.DefaultLevelWidth = *ldtk.DefaultLevelWidth
+	}
+	if ldtk.DefaultLevelHeight != nil {
+		w.DefaultLevelHeight = *ldtk.DefaultLevelHeight
+	}
+
+	return w
+}
+
+// levelRects returns the world rectangles of the levels. Linear layouts do
+// not save level positions, their levels follow each other from 0, 0.
+func levelRects(levels []Level, layout WorldLayout) []WorldRect {
+	rects := make([]WorldRect, len(levels))
+	x, y := 0, 0
+	for i, l := range levels {
+		rects[i] = WorldRect{X: l.WorldX(), Y: l.WorldY(), W: l.PxWidth(), H: l.PxHeight()}
+
+		switch layout {
+		case WorldLayoutLinearHorizontal:
+			rects[i].X, rects[i].Y = x, 0
+			x += rects[i].W
+		case WorldLayoutLinearVertical:
+			rects[i].X, rects[i].Y = 0, y
+			y += rects[i].H
+		}
+	}
+
+	return rects
+}